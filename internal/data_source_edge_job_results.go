@@ -0,0 +1,197 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// edgeJobLogsStatusNames maps Portainer EdgeJobLogsStatus values to readable names.
+var edgeJobLogsStatusNames = map[int]string{
+	1: "idle",
+	2: "pending",
+	3: "collected",
+}
+
+func dataSourceEdgeJobResults() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceEdgeJobResultsRead,
+
+		Schema: map[string]*schema.Schema{
+			"edge_job_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "ID of the Edge job",
+			},
+			"collect_logs": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Request log collection for every task and wait for the logs",
+			},
+			"logs_timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     120,
+				Description: "Maximum time in seconds to wait for logs to be collected",
+			},
+			"tasks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"task_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"endpoint_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"logs_status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Log collection status: idle, pending or collected",
+						},
+						"logs": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+type edgeJobTask struct {
+	ID         string `json:"Id"`
+	EndpointID int    `json:"EndpointId"`
+	LogsStatus int    `json:"LogsStatus"`
+}
+
+func dataSourceEdgeJobResultsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+	jobID := d.Get("edge_job_id").(int)
+	collectLogs := d.Get("collect_logs").(bool)
+
+	tasks, err := listEdgeJobTasks(client, jobID)
+	if err != nil {
+		return err
+	}
+
+	if collectLogs {
+		for _, task := range tasks {
+			if task.LogsStatus == 3 {
+				continue
+			}
+			path := fmt.Sprintf("/edge_jobs/%d/tasks/%d/logs", jobID, task.EndpointID)
+			resp, err := client.DoRequest(http.MethodPost, path, nil, nil)
+			if err != nil {
+				return fmt.Errorf("failed to request logs for endpoint %d: %w", task.EndpointID, err)
+			}
+			if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+				data, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				return fmt.Errorf("failed to request logs for endpoint %d: %s", task.EndpointID, string(data))
+			}
+			resp.Body.Close()
+		}
+
+		deadline := time.Now().Add(time.Duration(d.Get("logs_timeout").(int)) * time.Second)
+		for {
+			pending := false
+			for _, task := range tasks {
+				if task.LogsStatus != 3 {
+					pending = true
+					break
+				}
+			}
+			if !pending || time.Now().After(deadline) {
+				break
+			}
+			time.Sleep(3 * time.Second)
+
+			tasks, err = listEdgeJobTasks(client, jobID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	results := make([]map[string]interface{}, 0, len(tasks))
+	for _, task := range tasks {
+		status, ok := edgeJobLogsStatusNames[task.LogsStatus]
+		if !ok {
+			status = strconv.Itoa(task.LogsStatus)
+		}
+
+		logs := ""
+		if collectLogs && task.LogsStatus == 3 {
+			logs, err = getEdgeJobTaskLogs(client, jobID, task.EndpointID)
+			if err != nil {
+				return err
+			}
+		}
+
+		results = append(results, map[string]interface{}{
+			"task_id":     task.ID,
+			"endpoint_id": task.EndpointID,
+			"logs_status": status,
+			"logs":        logs,
+		})
+	}
+
+	d.SetId(strconv.Itoa(jobID))
+	if err := d.Set("tasks", results); err != nil {
+		return fmt.Errorf("failed to set tasks: %w", err)
+	}
+
+	return nil
+}
+
+func listEdgeJobTasks(client *APIClient, jobID int) ([]edgeJobTask, error) {
+	resp, err := client.DoRequest(http.MethodGet, fmt.Sprintf("/edge_jobs/%d/tasks", jobID), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list edge job tasks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to list edge job tasks: %s", string(data))
+	}
+
+	var tasks []edgeJobTask
+	if err := json.NewDecoder(resp.Body).Decode(&tasks); err != nil {
+		return nil, fmt.Errorf("failed to decode edge job tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+func getEdgeJobTaskLogs(client *APIClient, jobID, endpointID int) (string, error) {
+	path := fmt.Sprintf("/edge_jobs/%d/tasks/%d/logs", jobID, endpointID)
+	resp, err := client.DoRequest(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch logs for endpoint %d: %w", endpointID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to fetch logs for endpoint %d: %s", endpointID, string(data))
+	}
+
+	var result struct {
+		FileContent string `json:"FileContent"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode logs for endpoint %d: %w", endpointID, err)
+	}
+	return result.FileContent, nil
+}
@@ -0,0 +1,212 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// edgeStackStatusNames maps Portainer EdgeStackStatusType values to readable names.
+var edgeStackStatusNames = map[int]string{
+	0:  "pending",
+	1:  "deployment_received",
+	2:  "error",
+	3:  "acknowledged",
+	4:  "removed",
+	5:  "remote_update_success",
+	6:  "images_pulled",
+	7:  "running",
+	8:  "deploying",
+	9:  "removing",
+	10: "paused_deploying",
+	11: "paused_removing",
+	12: "completed",
+}
+
+func dataSourceEdgeStackStatus() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceEdgeStackStatusRead,
+
+		Schema: map[string]*schema.Schema{
+			"edge_stack_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "ID of the Edge stack",
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Current version of the Edge stack",
+			},
+			"all_deployed": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "True when every environment reports the stack as deployed",
+			},
+			"has_errors": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "True when at least one environment reports an error",
+			},
+			"environments": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"endpoint_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Summarized status: pending, deployed or error",
+						},
+						"status_details": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "All status entries reported by the environment, in order",
+						},
+						"error": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"images_pulled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"version": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Version of the stack deployed on the environment",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+type edgeStackEndpointStatus struct {
+	EndpointID int `json:"EndpointID"`
+	Status     []struct {
+		Type  int    `json:"Type"`
+		Error string `json:"Error"`
+		Time  int64  `json:"Time"`
+	} `json:"Status"`
+	DeploymentInfo struct {
+		Version     int `json:"Version"`
+		FileVersion int `json:"FileVersion"`
+	} `json:"DeploymentInfo"`
+}
+
+func dataSourceEdgeStackStatusRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+	stackID := d.Get("edge_stack_id").(int)
+
+	resp, err := client.DoRequest(http.MethodGet, fmt.Sprintf("/edge_stacks/%d", stackID), nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read edge stack: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to read edge stack %d: %s", stackID, string(data))
+	}
+
+	var stack struct {
+		Name    string                             `json:"Name"`
+		Version int                                `json:"Version"`
+		Status  map[string]edgeStackEndpointStatus `json:"Status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&stack); err != nil {
+		return fmt.Errorf("failed to decode edge stack: %w", err)
+	}
+
+	keys := make([]string, 0, len(stack.Status))
+	for k := range stack.Status {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, _ := strconv.Atoi(keys[i])
+		b, _ := strconv.Atoi(keys[j])
+		return a < b
+	})
+
+	allDeployed := len(keys) > 0
+	hasErrors := false
+	environments := make([]map[string]interface{}, 0, len(keys))
+
+	for _, k := range keys {
+		st := stack.Status[k]
+		endpointID := st.EndpointID
+		if endpointID == 0 {
+			endpointID, _ = strconv.Atoi(k)
+		}
+
+		summary := "pending"
+		errMsg := ""
+		imagesPulled := false
+		details := make([]string, 0, len(st.Status))
+
+		// Status is a history; the last entry is the current state.
+		for _, s := range st.Status {
+			name, ok := edgeStackStatusNames[s.Type]
+			if !ok {
+				name = strconv.Itoa(s.Type)
+			}
+			details = append(details, name)
+
+			switch s.Type {
+			case 2:
+				summary = "error"
+				errMsg = s.Error
+			case 5, 7, 12:
+				summary = "deployed"
+				errMsg = ""
+			case 6:
+				imagesPulled = true
+			default:
+				summary = "pending"
+			}
+		}
+
+		if summary == "error" {
+			hasErrors = true
+		}
+		if summary != "deployed" {
+			allDeployed = false
+		}
+
+		environments = append(environments, map[string]interface{}{
+			"endpoint_id":    endpointID,
+			"status":         summary,
+			"status_details": details,
+			"error":          errMsg,
+			"images_pulled":  imagesPulled,
+			"version":        st.DeploymentInfo.Version,
+		})
+	}
+
+	d.SetId(strconv.Itoa(stackID))
+	d.Set("name", stack.Name)
+	d.Set("version", stack.Version)
+	d.Set("all_deployed", allDeployed)
+	d.Set("has_errors", hasErrors)
+	if err := d.Set("environments", environments); err != nil {
+		return fmt.Errorf("failed to set environments: %w", err)
+	}
+
+	return nil
+}
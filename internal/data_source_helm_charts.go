@@ -0,0 +1,138 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceHelmCharts() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceHelmChartsRead,
+
+		Schema: map[string]*schema.Schema{
+			"repo": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "URL of the Helm repository to search",
+			},
+			"chart": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the chart with this name",
+			},
+			"charts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"latest_version": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"app_version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Application version of the latest chart version",
+						},
+						"versions": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "All chart versions, newest first",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+type helmChartVersion struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	AppVersion  string `json:"appVersion"`
+	Description string `json:"description"`
+}
+
+func dataSourceHelmChartsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+	repo := d.Get("repo").(string)
+	chart := d.Get("chart").(string)
+
+	query := url.Values{}
+	query.Set("repo", repo)
+	if chart != "" {
+		query.Set("chart", chart)
+	}
+
+	resp, err := client.DoRequest(http.MethodGet, "/templates/helm?"+query.Encode(), nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to search helm repository: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to search helm repository %s: %s", repo, string(data))
+	}
+
+	var index struct {
+		Entries map[string][]helmChartVersion `json:"entries"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&index); err != nil {
+		return fmt.Errorf("failed to decode helm repository index: %w", err)
+	}
+
+	names := make([]string, 0, len(index.Entries))
+	for name := range index.Entries {
+		if chart == "" || name == chart {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	if chart != "" && len(names) == 0 {
+		return fmt.Errorf("chart %q not found in helm repository %s", chart, repo)
+	}
+
+	// Entries in a repository index are ordered newest version first.
+	charts := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		entries := index.Entries[name]
+		if len(entries) == 0 {
+			continue
+		}
+		versions := make([]string, 0, len(entries))
+		for _, e := range entries {
+			versions = append(versions, e.Version)
+		}
+		charts = append(charts, map[string]interface{}{
+			"name":           name,
+			"description":    entries[0].Description,
+			"latest_version": entries[0].Version,
+			"app_version":    entries[0].AppVersion,
+			"versions":       versions,
+		})
+	}
+
+	d.SetId(repo + ":" + chart)
+	if err := d.Set("charts", charts); err != nil {
+		return fmt.Errorf("failed to set charts: %w", err)
+	}
+
+	return nil
+}
@@ -2,8 +2,14 @@ package internal
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"gopkg.in/yaml.v3"
 )
 
@@ -22,3 +28,71 @@ func parseManifest(manifest string) (map[string]interface{}, error) {
 
 	return nil, fmt.Errorf("manifest is neither valid JSON nor YAML")
 }
+
+// parseManifestDocuments parses a manifest holding one or more YAML documents
+// separated by "---"; a single JSON object is accepted too. Empty documents are
+// skipped. The YAML node of every document is returned alongside its content.
+func parseManifestDocuments(manifest string) ([]map[string]interface{}, []*yaml.Node, error) {
+	var docs []map[string]interface{}
+	var nodes []*yaml.Node
+
+	decoder := yaml.NewDecoder(strings.NewReader(manifest))
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("document %d: %w", len(docs)+1, err)
+		}
+
+		var doc map[string]interface{}
+		if err := node.Decode(&doc); err != nil {
+			return nil, nil, fmt.Errorf("document %d must be an object: %w", len(docs)+1, err)
+		}
+		if doc == nil {
+			continue
+		}
+		docs = append(docs, doc)
+		nodes = append(nodes, &node)
+	}
+
+	return docs, nodes, nil
+}
+
+// parseEndpointScopedImportID splits an import ID of the form "<endpointId>:<id>".
+func parseEndpointScopedImportID(id string) (int, string, error) {
+	parts := strings.SplitN(id, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return 0, "", fmt.Errorf("invalid import ID %q", id)
+	}
+	endpointID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid endpoint ID in import ID %q", id)
+	}
+	return endpointID, parts[1], nil
+}
+
+// suppressEquivalentYAML ignores formatting-only differences between stack files
+// and manifests, such as those Portainer introduces when it re-serializes them;
+// only semantic YAML changes are a diff.
+func suppressEquivalentYAML(k, old, new string, d *schema.ResourceData) bool {
+	return yamlEquivalent(old, new)
+}
+
+// yamlEquivalent reports whether two YAML (or JSON) documents are semantically equal,
+// ignoring formatting, comments and key order. Unparsable input is compared verbatim.
+func yamlEquivalent(a, b string) bool {
+	if strings.TrimSpace(a) == strings.TrimSpace(b) {
+		return true
+	}
+
+	var va, vb interface{}
+	if err := yaml.Unmarshal([]byte(a), &va); err != nil {
+		return false
+	}
+	if err := yaml.Unmarshal([]byte(b), &vb); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,206 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Resource control types of the Portainer API.
+const (
+	resourceControlTypeVolume  = 3
+	resourceControlTypeNetwork = 4
+	resourceControlTypeSecret  = 5
+	resourceControlTypeStack   = 6
+	resourceControlTypeConfig  = 7
+)
+
+// accessControlSchema is the access_control block of resources that Portainer
+// guards with a resource control.
+func accessControlSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Who may use the resource in Portainer; without this block the resource control is left as is",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"administrators_only": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Restrict access to administrators",
+				},
+				"public": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Allow access to all users",
+				},
+				"teams": {
+					Type:        schema.TypeSet,
+					Optional:    true,
+					Elem:        &schema.Schema{Type: schema.TypeInt},
+					Description: "IDs of the teams allowed to access the resource",
+				},
+				"users": {
+					Type:        schema.TypeSet,
+					Optional:    true,
+					Elem:        &schema.Schema{Type: schema.TypeInt},
+					Description: "IDs of the users allowed to access the resource",
+				},
+			},
+		},
+	}
+}
+
+// portainerResourceControl is a resource control as embedded by Portainer in
+// stacks and in responses of the Docker proxy.
+type portainerResourceControl struct {
+	ID                 int    `json:"Id"`
+	ResourceID         string `json:"ResourceId"`
+	Type               int    `json:"Type"`
+	AdministratorsOnly bool   `json:"AdministratorsOnly"`
+	Public             bool   `json:"Public"`
+	UserAccesses       []struct {
+		UserID int `json:"UserId"`
+	} `json:"UserAccesses"`
+	TeamAccesses []struct {
+		TeamID int `json:"TeamId"`
+	} `json:"TeamAccesses"`
+}
+
+// setAccessControl stores rc in access_control. The block is only tracked
+// once it is configured, so resources without it keep Portainer's defaults.
+func setAccessControl(d *schema.ResourceData, rc *portainerResourceControl) error {
+	if len(d.Get("access_control").([]interface{})) == 0 {
+		return nil
+	}
+
+	block := map[string]interface{}{
+		"administrators_only": false,
+		"public":              false,
+		"teams":               []interface{}{},
+		"users":               []interface{}{},
+	}
+	if rc != nil {
+		teams := make([]interface{}, 0, len(rc.TeamAccesses))
+		for _, t := range rc.TeamAccesses {
+			teams = append(teams, t.TeamID)
+		}
+		users := make([]interface{}, 0, len(rc.UserAccesses))
+		for _, u := range rc.UserAccesses {
+			users = append(users, u.UserID)
+		}
+		block["administrators_only"] = rc.AdministratorsOnly
+		block["public"] = rc.Public
+		block["teams"] = teams
+		block["users"] = users
+	}
+
+	if err := d.Set("access_control", []interface{}{block}); err != nil {
+		return fmt.Errorf("failed to set access_control: %w", err)
+	}
+	return nil
+}
+
+// applyAccessControl updates rc, the current resource control of the
+// resource, to match access_control. If the resource has no resource control
+// yet, one is created for resourceID.
+func applyAccessControl(d *schema.ResourceData, client *APIClient, rc *portainerResourceControl, resourceID string, rcType int) error {
+	blocks := d.Get("access_control").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return nil
+	}
+	block := blocks[0].(map[string]interface{})
+
+	teams := expandIntSet(block["teams"].(*schema.Set))
+	users := expandIntSet(block["users"].(*schema.Set))
+	body := map[string]interface{}{
+		"administratorsOnly": block["administrators_only"].(bool),
+		"public":             block["public"].(bool),
+		"teams":              teams,
+		"users":              users,
+	}
+
+	method, path := http.MethodPost, "/resource_controls"
+	if rc != nil {
+		method, path = http.MethodPut, fmt.Sprintf("/resource_controls/%d", rc.ID)
+	} else {
+		body["resourceID"] = resourceID
+		body["type"] = rcType
+	}
+
+	resp, err := client.DoRequest(method, path, nil, body)
+	if err != nil {
+		return fmt.Errorf("failed to set access control: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to set access control: %s", string(data))
+	}
+	return nil
+}
+
+// dockerResourceControl returns the resource control Portainer attaches to the
+// Docker object at path, or nil if it has none.
+func dockerResourceControl(client *APIClient, path string) (*portainerResourceControl, error) {
+	resp, err := client.DoRequest(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to read resource control: %s", string(data))
+	}
+
+	var obj portainerDecoration
+	if err := json.NewDecoder(resp.Body).Decode(&obj); err != nil {
+		return nil, err
+	}
+	return obj.resourceControl(), nil
+}
+
+// portainerDecoration is the field Portainer adds to the objects returned by
+// the Docker proxy. Embed it in a response struct to decode it.
+type portainerDecoration struct {
+	Portainer *struct {
+		ResourceControl *portainerResourceControl `json:"ResourceControl"`
+	} `json:"Portainer"`
+}
+
+func (p portainerDecoration) resourceControl() *portainerResourceControl {
+	if p.Portainer == nil {
+		return nil
+	}
+	return p.Portainer.ResourceControl
+}
+
+// updateDockerAccessControl applies access_control to the Docker object at path.
+func updateDockerAccessControl(d *schema.ResourceData, client *APIClient, path, resourceID string, rcType int) error {
+	if len(d.Get("access_control").([]interface{})) == 0 {
+		return nil
+	}
+	rc, err := dockerResourceControl(client, path)
+	if err != nil {
+		return err
+	}
+	return applyAccessControl(d, client, rc, resourceID, rcType)
+}
+
+func expandIntSet(set *schema.Set) []int {
+	out := make([]int, 0, set.Len())
+	for _, v := range set.List() {
+		out = append(out, v.(int))
+	}
+	sort.Ints(out)
+	return out
+}
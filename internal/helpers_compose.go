@@ -0,0 +1,203 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/interpolation"
+	"github.com/compose-spec/compose-go/v2/schema"
+	"github.com/compose-spec/compose-go/v2/template"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"gopkg.in/yaml.v3"
+)
+
+// swarmIgnoredServiceKeys are service keys that `docker stack deploy` ignores,
+// with what to use instead where there is an equivalent.
+var swarmIgnoredServiceKeys = map[string]string{
+	"build":          "build the image beforehand and reference it with image",
+	"cgroup_parent":  "",
+	"container_name": "swarm names the tasks of a service itself",
+	"depends_on":     "swarm starts services in no particular order",
+	"devices":        "",
+	"external_links": "",
+	"links":          "use a shared network",
+	"network_mode":   "use networks",
+	"restart":        "use deploy.restart_policy",
+	"security_opt":   "",
+	"userns_mode":    "",
+}
+
+// swarmForbiddenServiceKeys are service keys that `docker stack deploy` rejects.
+var swarmForbiddenServiceKeys = map[string]string{
+	"cpu_quota":     "use deploy.resources.limits.cpus",
+	"cpu_shares":    "use deploy.resources.reservations.cpus",
+	"cpuset":        "",
+	"extends":       "",
+	"mem_limit":     "use deploy.resources.limits.memory",
+	"memswap_limit": "",
+	"volume_driver": "",
+	"volumes_from":  "",
+}
+
+// validateComposeFile checks content against the compose specification, after
+// substituting the variables in env. env is nil when the resource has no
+// environment variables to check, which skips interpolation. It returns a
+// warning for every variable that is neither in env nor has a default. With
+// swarm set, service keys that swarm rejects are errors too.
+func validateComposeFile(content string, env map[string]string, swarm bool) ([]string, error) {
+	dict, err := parseComposeFile(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	if env != nil {
+		vars := template.ExtractVariables(dict, nil)
+		names := make([]string, 0, len(vars))
+		for name := range vars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			v := vars[name]
+			if _, ok := env[name]; !ok && v.DefaultValue == "" && !v.Required {
+				warnings = append(warnings, fmt.Sprintf("variable %q is not set in env and defaults to a blank string", name))
+			}
+		}
+
+		dict, err = interpolation.Interpolate(dict, interpolation.Options{
+			LookupValue: func(key string) (string, bool) {
+				value, ok := env[key]
+				return value, ok
+			},
+			Substitute: func(s string, mapping template.Mapping) (string, error) {
+				return template.SubstituteWithOptions(s, mapping, template.WithoutLogging)
+			},
+		})
+		if err != nil {
+			return warnings, err
+		}
+	}
+
+	if err := schema.Validate(dict); err != nil {
+		return warnings, err
+	}
+
+	if swarm {
+		if problems := swarmServiceKeyProblems(dict, swarmForbiddenServiceKeys, "is not supported by swarm"); len(problems) > 0 {
+			return warnings, fmt.Errorf("%s", strings.Join(problems, "; "))
+		}
+	}
+
+	return warnings, nil
+}
+
+// composeWarningDiagnostics returns the warnings about the compose file set by
+// attr: keys swarm ignores when swarm is set, and variables missing from env.
+// Errors are left to the CustomizeDiff of the resource.
+func composeWarningDiagnostics(attr, content string, env map[string]string, swarm bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if swarm {
+		for _, w := range swarmIgnoredKeyWarnings(content) {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Warning,
+				Summary:       "Compose key ignored by swarm",
+				Detail:        w,
+				AttributePath: cty.GetAttrPath(attr),
+			})
+		}
+	}
+	warnings, _ := validateComposeFile(content, env, false)
+	for _, w := range warnings {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Warning,
+			Summary:       "Compose variable not set",
+			Detail:        w,
+			AttributePath: cty.GetAttrPath(attr),
+		})
+	}
+	return diags
+}
+
+// swarmIgnoredKeyWarnings returns a warning for every service key of content
+// that `docker stack deploy` ignores. Invalid content has no warnings; it is
+// reported by validateComposeFile.
+func swarmIgnoredKeyWarnings(content string) []string {
+	dict, err := parseComposeFile(content)
+	if err != nil {
+		return nil
+	}
+	return swarmServiceKeyProblems(dict, swarmIgnoredServiceKeys, "is ignored by swarm")
+}
+
+// swarmServiceKeyProblems describes every service key of dict that is in keys.
+func swarmServiceKeyProblems(dict map[string]interface{}, keys map[string]string, problem string) []string {
+	services, _ := dict["services"].(map[string]interface{})
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		service, _ := services[name].(map[string]interface{})
+		serviceKeys := make([]string, 0, len(service))
+		for key := range service {
+			serviceKeys = append(serviceKeys, key)
+		}
+		sort.Strings(serviceKeys)
+		for _, key := range serviceKeys {
+			hint, found := keys[key]
+			if !found {
+				continue
+			}
+			p := fmt.Sprintf("services.%s.%s %s", name, key, problem)
+			if hint != "" {
+				p += " (" + hint + ")"
+			}
+			problems = append(problems, p)
+		}
+	}
+	return problems
+}
+
+// parseComposeFile decodes content into the string-keyed maps the compose
+// schema expects.
+func parseComposeFile(content string) (map[string]interface{}, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal([]byte(content), &raw); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	dict, ok := normalizeComposeValue(raw).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the top level of a compose file must be a mapping")
+	}
+	return dict, nil
+}
+
+// normalizeComposeValue converts mappings with non-string keys, such as numeric
+// keys, to the string-keyed maps the compose schema expects.
+func normalizeComposeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeComposeValue(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[fmt.Sprint(key)] = normalizeComposeValue(item)
+		}
+		return out
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeComposeValue(item)
+		}
+		return v
+	}
+	return value
+}
@@ -0,0 +1,313 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// envFileContentSchema is the env_file_content attribute of stacks.
+func envFileContentSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Environment variables in dotenv format",
+		ValidateFunc: func(v interface{}, k string) ([]string, []error) {
+			if _, err := parseDotenv(v.(string)); err != nil {
+				return nil, []error{fmt.Errorf("%s: %w", k, err)}
+			}
+			return nil, nil
+		},
+	}
+}
+
+// sensitiveEnvSchema is the sensitive_env attribute of stacks.
+func sensitiveEnvSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Optional:    true,
+		Sensitive:   true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "Environment variables whose values are hidden in plans and output",
+	}
+}
+
+// sensitiveEnvRevisionsSchema tracks a revision per sensitive_env key, so plans
+// show which keys change without revealing their values.
+func sensitiveEnvRevisionsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "Revision of each sensitive_env key, incremented when its value changes",
+	}
+}
+
+// sensitiveEnvRevisionsCustomizeDiff bumps the revision of every sensitive_env
+// key whose value changes.
+func sensitiveEnvRevisionsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("sensitive_env") {
+		return nil
+	}
+	if !d.NewValueKnown("sensitive_env") {
+		return d.SetNewComputed("sensitive_env_revisions")
+	}
+
+	o, n := d.GetChange("sensitive_env")
+	oldEnv := o.(map[string]interface{})
+	revisions := d.Get("sensitive_env_revisions").(map[string]interface{})
+
+	out := map[string]interface{}{}
+	for key, value := range n.(map[string]interface{}) {
+		revision, _ := strconv.Atoi(fmt.Sprint(revisions[key]))
+		if old, ok := oldEnv[key]; !ok || old != value || revision == 0 {
+			revision++
+		}
+		out[key] = strconv.Itoa(revision)
+	}
+	return d.SetNew("sensitive_env_revisions", out)
+}
+
+// mergeEnvSources combines the variables of env_file_content, the env blocks
+// and sensitive_env into one list. Later sources win: env overrides the
+// dotenv content and sensitive_env overrides both.
+func mergeEnvSources(envFile string, env []interface{}, sensitive map[string]interface{}) []map[string]string {
+	var merged []map[string]string
+	index := map[string]int{}
+	add := func(name, value string) {
+		if i, ok := index[name]; ok {
+			merged[i]["value"] = value
+			return
+		}
+		index[name] = len(merged)
+		merged = append(merged, map[string]string{"name": name, "value": value})
+	}
+
+	// env_file_content is validated at plan time.
+	pairs, _ := parseDotenv(envFile)
+	for _, p := range pairs {
+		add(p[0], p[1])
+	}
+	for _, e := range flattenEnvList(env) {
+		add(e["name"], e["value"])
+	}
+	keys := make([]string, 0, len(sensitive))
+	for k := range sensitive {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		add(k, sensitive[k].(string))
+	}
+	return merged
+}
+
+// rawConfigEnv merges the env_file_content, env and sensitive_env attributes of
+// a raw resource configuration like mergeEnvSources. Attributes the resource does
+// not have are skipped. It returns nil while any of them is unknown.
+func rawConfigEnv(config cty.Value) map[string]string {
+	var envFile string
+	var env []interface{}
+	sensitive := map[string]interface{}{}
+	for _, attr := range []string{"env_file_content", "env", "sensitive_env"} {
+		if !config.Type().HasAttribute(attr) {
+			continue
+		}
+		v := config.GetAttr(attr)
+		if !v.IsWhollyKnown() {
+			return nil
+		}
+		if v.IsNull() {
+			continue
+		}
+		switch attr {
+		case "env_file_content":
+			envFile = v.AsString()
+		case "env":
+			for it := v.ElementIterator(); it.Next(); {
+				_, e := it.Element()
+				env = append(env, map[string]interface{}{
+					"name":  rawConfigString(e.GetAttr("name")),
+					"value": rawConfigString(e.GetAttr("value")),
+				})
+			}
+		case "sensitive_env":
+			for k, e := range v.AsValueMap() {
+				sensitive[k] = rawConfigString(e)
+			}
+		}
+	}
+
+	out := map[string]string{}
+	for _, e := range mergeEnvSources(envFile, env, sensitive) {
+		out[e["name"]] = e["value"]
+	}
+	return out
+}
+
+// rawConfigString returns a known string value of a raw configuration, or "".
+func rawConfigString(v cty.Value) string {
+	if v.IsNull() || !v.IsKnown() {
+		return ""
+	}
+	return v.AsString()
+}
+
+// splitEnvSources hands the variables Portainer reports back to the attribute
+// each came from, mirroring the precedence of mergeEnvSources. Variables not
+// claimed by sensitive_env or the dotenv content are returned in env.
+// envFile is the dotenv content to store: unchanged unless a variable from it
+// was changed or removed outside of Terraform.
+func splitEnvSources(actual []map[string]string, envFile string, env []interface{}, sensitive map[string]interface{}) (string, []map[string]interface{}, map[string]interface{}) {
+	blockNames := map[string]bool{}
+	for _, e := range flattenEnvList(env) {
+		blockNames[e["name"]] = true
+	}
+	pairs, _ := parseDotenv(envFile)
+	fileNames := map[string]bool{}
+	for _, p := range pairs {
+		fileNames[p[0]] = true
+	}
+
+	outEnv := []map[string]interface{}{}
+	outSensitive := map[string]interface{}{}
+	fileValues := map[string]string{}
+	for _, e := range actual {
+		name, value := e["name"], e["value"]
+		if _, ok := sensitive[name]; ok {
+			outSensitive[name] = value
+		} else if !blockNames[name] && fileNames[name] {
+			fileValues[name] = value
+		} else {
+			outEnv = append(outEnv, map[string]interface{}{"name": name, "value": value})
+		}
+	}
+
+	drift := false
+	var lines []string
+	for _, p := range pairs {
+		if _, ok := sensitive[p[0]]; ok || blockNames[p[0]] {
+			continue
+		}
+		value, ok := fileValues[p[0]]
+		if !ok {
+			drift = true
+			continue
+		}
+		if value != p[1] {
+			drift = true
+		}
+		lines = append(lines, p[0]+"="+formatDotenvValue(value))
+	}
+	if drift {
+		envFile = ""
+		if len(lines) > 0 {
+			envFile = strings.Join(lines, "\n") + "\n"
+		}
+	}
+	return envFile, outEnv, outSensitive
+}
+
+// parseDotenv parses dotenv content into name/value pairs, in order. It
+// supports comments, "export" prefixes, single-quoted literal values and
+// double-quoted values with \n, \t, \" and \\ escapes.
+func parseDotenv(content string) ([][2]string, error) {
+	var pairs [][2]string
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("line %d: expected NAME=value", i+1)
+		}
+		name := strings.TrimSpace(line[:eq])
+		if strings.ContainsAny(name, " \t\"'") {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", i+1, name)
+		}
+		raw := strings.TrimSpace(line[eq+1:])
+
+		var value string
+		switch {
+		case strings.HasPrefix(raw, `"`) || strings.HasPrefix(raw, "'"):
+			quote := raw[0]
+			body := raw[1:]
+			// Quoted values may span several lines.
+			start := i
+			for closingQuote(body, quote) < 0 {
+				if i+1 >= len(lines) {
+					return nil, fmt.Errorf("line %d: unterminated quoted value", start+1)
+				}
+				i++
+				body += "\n" + lines[i]
+			}
+			end := closingQuote(body, quote)
+			value = body[:end]
+			if quote == '"' {
+				value = unescapeDotenv(value)
+			}
+		default:
+			// An unquoted value ends at an inline comment.
+			if idx := strings.Index(raw, " #"); idx >= 0 {
+				raw = raw[:idx]
+			}
+			value = strings.TrimSpace(raw)
+		}
+		pairs = append(pairs, [2]string{name, value})
+	}
+	return pairs, nil
+}
+
+// closingQuote returns the index of the unescaped quote ending s, or -1.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && quote == '"' {
+			i++
+			continue
+		}
+		if s[i] == quote {
+			return i
+		}
+	}
+	return -1
+}
+
+func unescapeDotenv(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// formatDotenvValue quotes value if parseDotenv would not read it back as is.
+func formatDotenvValue(value string) string {
+	if value == strings.TrimSpace(value) && !strings.ContainsAny(value, "\"'#\\\n\r\t") {
+		return value
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(value) + `"`
+}
@@ -0,0 +1,239 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// composeHelmValues builds the values of a Helm release the way the helm CLI
+// does: values_files are merged in order, then values, then every set and
+// set_sensitive entry is applied with --set path semantics. get reads an
+// attribute from schema.ResourceData or schema.ResourceDiff.
+func composeHelmValues(get func(string) interface{}) (string, error) {
+	values := get("values").(string)
+	// Send a lone values document unchanged.
+	if !usesHelmValuesComposition(get) {
+		return values, nil
+	}
+
+	files := get("values_files").([]interface{})
+	sets := get("set").([]interface{})
+	sensitive := get("set_sensitive").([]interface{})
+
+	merged := map[string]interface{}{}
+	for i, raw := range append(files, values) {
+		doc, _ := raw.(string)
+		var parsed map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &parsed); err != nil {
+			if i == len(files) {
+				return "", fmt.Errorf("values must be a YAML mapping: %w", err)
+			}
+			return "", fmt.Errorf("values_files[%d] must be a YAML mapping: %w", i, err)
+		}
+		mergeHelmValues(merged, parsed)
+	}
+
+	for _, raw := range append(sets, sensitive...) {
+		entry := raw.(map[string]interface{})
+		name := entry["name"].(string)
+		value := parseHelmSetValue(entry["value"].(string), entry["type"].(string))
+		if err := setHelmValue(merged, name, value); err != nil {
+			return "", err
+		}
+	}
+
+	out, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode values: %w", err)
+	}
+	return string(out), nil
+}
+
+// usesHelmValuesComposition reports whether values are layered from more than the values attribute.
+func usesHelmValuesComposition(get func(string) interface{}) bool {
+	return len(get("values_files").([]interface{})) > 0 || len(get("set").([]interface{})) > 0 ||
+		len(get("set_sensitive").([]interface{})) > 0
+}
+
+// mergeHelmValues deep-merges src into dst. Maps are merged key by key; a null
+// value deletes the key, like in helm values files; any other value in src
+// replaces the one in dst.
+func mergeHelmValues(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if v == nil {
+			delete(dst, k)
+			continue
+		}
+		if sv, ok := v.(map[string]interface{}); ok {
+			if dv, ok := dst[k].(map[string]interface{}); ok {
+				mergeHelmValues(dv, sv)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+// helmPathPart is one segment of a --set path: a map key or a list index.
+type helmPathPart struct {
+	key   string
+	index int
+	list  bool
+}
+
+// parseHelmSetPath splits a --set name such as "ingress.hosts[0].name" into its
+// segments. A backslash escapes the next character, e.g. "podAnnotations.prometheus\.io/scrape".
+func parseHelmSetPath(name string) ([]helmPathPart, error) {
+	var parts []helmPathPart
+	var key strings.Builder
+	pending := false
+
+	for i := 0; i < len(name); i++ {
+		switch c := name[i]; c {
+		case '\\':
+			if i+1 < len(name) {
+				i++
+				key.WriteByte(name[i])
+				pending = true
+			}
+		case '.':
+			if pending {
+				parts = append(parts, helmPathPart{key: key.String()})
+				key.Reset()
+				pending = false
+			}
+		case '[':
+			if pending {
+				parts = append(parts, helmPathPart{key: key.String()})
+				key.Reset()
+				pending = false
+			}
+			end := strings.IndexByte(name[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid set name %q: missing ]", name)
+			}
+			index, err := strconv.Atoi(name[i+1 : i+end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid set name %q: bad list index", name)
+			}
+			parts = append(parts, helmPathPart{index: index, list: true})
+			i += end
+		default:
+			key.WriteByte(c)
+			pending = true
+		}
+	}
+	if pending {
+		parts = append(parts, helmPathPart{key: key.String()})
+	}
+	if len(parts) == 0 || parts[0].list {
+		return nil, fmt.Errorf("invalid set name %q", name)
+	}
+	return parts, nil
+}
+
+// setHelmValue sets the value at the --set path name in values, creating
+// intermediate maps and lists as needed. A nil value deletes the key, as
+// key=null does with the helm CLI, and creates nothing.
+func setHelmValue(values map[string]interface{}, name string, value interface{}) error {
+	parts, err := parseHelmSetPath(name)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		deleteHelmPath(values, parts)
+		return nil
+	}
+	setHelmPath(values, parts, value)
+	return nil
+}
+
+// deleteHelmPath removes the key at the end of parts, or clears the list item,
+// if the path exists in current.
+func deleteHelmPath(current interface{}, parts []helmPathPart) {
+	for i, part := range parts {
+		last := i == len(parts)-1
+		if part.list {
+			list, ok := current.([]interface{})
+			if !ok || part.index >= len(list) {
+				return
+			}
+			if last {
+				list[part.index] = nil
+				return
+			}
+			current = list[part.index]
+			continue
+		}
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return
+		}
+		if last {
+			delete(m, part.key)
+			return
+		}
+		current = m[part.key]
+	}
+}
+
+func setHelmPath(current interface{}, parts []helmPathPart, value interface{}) interface{} {
+	if len(parts) == 0 {
+		return value
+	}
+
+	part := parts[0]
+	if part.list {
+		list, _ := current.([]interface{})
+		for len(list) <= part.index {
+			list = append(list, nil)
+		}
+		list[part.index] = setHelmPath(list[part.index], parts[1:], value)
+		return list
+	}
+
+	m, ok := current.(map[string]interface{})
+	if !ok {
+		m = map[string]interface{}{}
+	}
+	m[part.key] = setHelmPath(m[part.key], parts[1:], value)
+	return m
+}
+
+// parseHelmSetValue converts a --set value. With type "string" the value is
+// kept as is; otherwise booleans, null, integers and {a,b} lists are recognized
+// like the helm CLI does.
+func parseHelmSetValue(value, valueType string) interface{} {
+	if valueType == "string" {
+		return value
+	}
+
+	if strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}") {
+		inner := value[1 : len(value)-1]
+		list := []interface{}{}
+		if inner == "" {
+			return list
+		}
+		for _, item := range strings.Split(inner, ",") {
+			list = append(list, parseHelmSetValue(item, valueType))
+		}
+		return list
+	}
+
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	// Like helm, keep values with a leading zero (e.g. "0755") as strings.
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil && (value == "0" || !strings.HasPrefix(strings.TrimPrefix(value, "-"), "0")) {
+		return n
+	}
+	return value
+}
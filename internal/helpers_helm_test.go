@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestComposeHelmValuesSetNullDeletesKey(t *testing.T) {
+	attrs := map[string]interface{}{
+		"values":       "image:\n  tag: \"1.0\"\n  pullPolicy: Always\nreplicas: 2\n",
+		"values_files": []interface{}{},
+		"set": []interface{}{
+			map[string]interface{}{"name": "image.pullPolicy", "value": "null", "type": "auto"},
+			map[string]interface{}{"name": "replicas", "value": "null", "type": "auto"},
+			map[string]interface{}{"name": "missing.key", "value": "null", "type": "auto"},
+		},
+		"set_sensitive": []interface{}{},
+	}
+	out, err := composeHelmValues(func(k string) interface{} { return attrs[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var values map[string]interface{}
+	if err := yaml.Unmarshal([]byte(out), &values); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := values["replicas"]; ok {
+		t.Errorf("replicas=null should delete the key, got %v", values)
+	}
+	image := values["image"].(map[string]interface{})
+	if _, ok := image["pullPolicy"]; ok {
+		t.Errorf("image.pullPolicy=null should delete the key, got %v", image)
+	}
+	if image["tag"] != "1.0" {
+		t.Errorf("image.tag was lost: %v", image)
+	}
+	if _, ok := values["missing"]; ok {
+		t.Errorf("missing.key=null should not create missing, got %v", values)
+	}
+}
+
+func TestComposeHelmValuesSetNullOnMissingPathChangesNothing(t *testing.T) {
+	attrs := map[string]interface{}{
+		"values":       "replicas: 2\n",
+		"values_files": []interface{}{},
+		"set": []interface{}{
+			map[string]interface{}{"name": "missing.key", "value": "null", "type": "auto"},
+			map[string]interface{}{"name": "replicas.nested", "value": "null", "type": "auto"},
+			map[string]interface{}{"name": "hosts[3]", "value": "null", "type": "auto"},
+		},
+		"set_sensitive": []interface{}{},
+	}
+	out, err := composeHelmValues(func(k string) interface{} { return attrs[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "replicas: 2\n" {
+		t.Errorf("null on a missing path should leave values untouched, got %q", out)
+	}
+}
+
+func TestComposeHelmValuesFileNullDeletesKey(t *testing.T) {
+	attrs := map[string]interface{}{
+		"values": "image:\n  pullPolicy: null\n",
+		"values_files": []interface{}{
+			"image:\n  tag: \"1.0\"\n  pullPolicy: Always\nreplicas: 2\n",
+			"replicas: null\n",
+		},
+		"set":           []interface{}{},
+		"set_sensitive": []interface{}{},
+	}
+	out, err := composeHelmValues(func(k string) interface{} { return attrs[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "image:\n    tag: \"1.0\"\n" {
+		t.Errorf("null in a values file should delete the key, got %q", out)
+	}
+}
+
+func TestComposeHelmValuesSetNullAsString(t *testing.T) {
+	attrs := map[string]interface{}{
+		"values":       "",
+		"values_files": []interface{}{},
+		"set": []interface{}{
+			map[string]interface{}{"name": "mode", "value": "null", "type": "string"},
+		},
+		"set_sensitive": []interface{}{},
+	}
+	out, err := composeHelmValues(func(k string) interface{} { return attrs[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "mode: \"null\"\n" {
+		t.Errorf("type = \"string\" should keep null as a string, got %q", out)
+	}
+}
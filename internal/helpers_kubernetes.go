@@ -0,0 +1,636 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// kubernetesFieldManager identifies this provider in server-side apply requests.
+const kubernetesFieldManager = "terraform-provider-portainer"
+
+// secretValueChanged replaces the value of a Secret key that changed outside
+// Terraform when drift is rendered, so the live value never reaches the plan.
+const secretValueChanged = "(changed outside of Terraform)"
+
+// kubernetesImmutableFieldError matches the reasons the API server gives for
+// refusing to update a field: "field is immutable" (e.g. a Job's template),
+// "cannot change roleRef" and "updates to provisioner are forbidden" (StorageClass).
+var kubernetesImmutableFieldError = regexp.MustCompile(`immutable|cannot change roleRef|updates to \S+ are forbidden`)
+
+// updateKubernetesManifest updates the object at path in place using server-side
+// apply. It reports recreate=true, without changing anything, when the object has
+// to be replaced instead: the manifest renames it, or the change touches a field
+// the API server refuses to update (e.g. a Service's clusterIP or a Job's template).
+func updateKubernetesManifest(d *schema.ResourceData, client *APIClient, path, name string) (bool, error) {
+	parsed, err := parseManifest(d.Get("manifest").(string))
+	if err != nil {
+		return false, fmt.Errorf("manifest must be valid JSON or YAML: %w", err)
+	}
+	metadata, ok := parsed["metadata"].(map[string]interface{})
+	if !ok {
+		return false, fmt.Errorf("missing metadata in manifest")
+	}
+	if newName, _ := metadata["name"].(string); newName != name {
+		return true, nil
+	}
+
+	return applyKubernetesObject(client, path, parsed)
+}
+
+// applyKubernetesObject sends obj to path as a server-side apply patch. It reports
+// recreate=true when the API server rejects the change because it touches an
+// immutable field.
+func applyKubernetesObject(client *APIClient, path string, obj map[string]interface{}) (bool, error) {
+	query := url.Values{}
+	query.Set("fieldManager", kubernetesFieldManager)
+	query.Set("force", "true")
+	headers := map[string]string{"Content-Type": "application/apply-patch+yaml"}
+
+	resp, err := client.DoRequest(http.MethodPatch, path+"?"+query.Encode(), headers, obj)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply Kubernetes object: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == 422 && kubernetesImmutableFieldError.Match(body) {
+		return true, nil
+	}
+	return false, fmt.Errorf("failed to apply Kubernetes object (%d): %s", resp.StatusCode, string(body))
+}
+
+// createKubernetesObject creates obj at path through the same server-side apply
+// as later updates, so the provider's field manager owns every field it sets and
+// fields removed from the manifest are pruned. Unlike an apply, it fails when the
+// object already exists.
+func createKubernetesObject(client *APIClient, path string, obj map[string]interface{}) error {
+	live, err := getKubernetesObject(client, path)
+	if err != nil {
+		return err
+	}
+	if live != nil {
+		return fmt.Errorf("Kubernetes object %s already exists", path)
+	}
+
+	if _, err := applyKubernetesObject(client, path, obj); err != nil {
+		return fmt.Errorf("failed to create Kubernetes object: %w", err)
+	}
+	return nil
+}
+
+// getKubernetesObject fetches an object through the Portainer Kubernetes proxy.
+// path is relative to the API root, e.g. /endpoints/1/kubernetes/api/v1/namespaces/default/secrets/foo.
+// It returns nil without error when the object does not exist.
+func getKubernetesObject(client *APIClient, path string) (map[string]interface{}, error) {
+	resp, err := client.DoRequest(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Kubernetes object: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, nil
+	} else if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to read Kubernetes object (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var obj map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&obj); err != nil {
+		return nil, fmt.Errorf("failed to decode Kubernetes object: %w", err)
+	}
+	return obj, nil
+}
+
+// readKubernetesManifest refreshes the "manifest" attribute from the live object at path.
+// Only fields written in the configured manifest are compared, so server-managed
+// and defaulted fields are ignored. When the live object differs, the manifest is
+// replaced by the live values rendered in the layout of the configured manifest,
+// so the plan shows a precise diff. A missing object is removed from state.
+func readKubernetesManifest(d *schema.ResourceData, client *APIClient, path string) error {
+	obj, err := getKubernetesObject(client, path)
+	if err != nil {
+		return err
+	}
+	if obj == nil {
+		d.SetId("")
+		return nil
+	}
+
+	manifest := d.Get("manifest").(string)
+	desired, err := parseManifest(manifest)
+	if err != nil {
+		// Leave an unparsable manifest alone; Create/Update report the error.
+		return nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(manifest), &doc); err != nil {
+		return nil
+	}
+	out, drifted, err := renderKubernetesDrift(&doc, desired, obj)
+	if err != nil {
+		return err
+	}
+	if drifted {
+		d.Set("manifest", out)
+	}
+	return nil
+}
+
+// renderKubernetesDrift compares the fields of desired with the live object. When
+// they differ it returns the live values rendered in the layout of doc, the YAML
+// node desired was decoded from.
+func renderKubernetesDrift(doc *yaml.Node, desired, live map[string]interface{}) (string, bool, error) {
+	desiredValue := toJSONValue(secretStringDataToData(desired))
+	projected := projectKubernetesValue(desiredValue, live)
+	if reflect.DeepEqual(desiredValue, projected) {
+		return "", false, nil
+	}
+
+	if obj, ok := projected.(map[string]interface{}); ok {
+		projected = secretDataToStringData(desired, obj)
+	}
+	out, err := encodeKubernetesYAML(kubernetesDriftNode(doc, projected))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to encode live manifest: %w", err)
+	}
+	return out, true, nil
+}
+
+// projectKubernetesValue returns the parts of live that are present in desired.
+// Lists of different length are returned whole.
+func projectKubernetesValue(desired, live interface{}) interface{} {
+	switch dv := desired.(type) {
+	case map[string]interface{}:
+		lv, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		out := make(map[string]interface{}, len(dv))
+		for k, v := range dv {
+			if l, ok := lv[k]; ok {
+				out[k] = projectKubernetesValue(v, l)
+			}
+		}
+		return out
+	case []interface{}:
+		lv, ok := live.([]interface{})
+		if !ok || len(lv) != len(dv) {
+			return live
+		}
+		out := make([]interface{}, len(dv))
+		for i := range dv {
+			out[i] = projectKubernetesValue(dv[i], lv[i])
+		}
+		return out
+	default:
+		// The API server canonicalizes quantities, e.g. "0.5" becomes "500m".
+		if ls, ok := live.(string); ok && kubernetesQuantityEqual(desired, ls) {
+			return desired
+		}
+		return live
+	}
+}
+
+var kubernetesQuantitySuffixes = map[string]float64{
+	"n": 1e-9, "u": 1e-6, "m": 1e-3, "": 1,
+	"k": 1e3, "M": 1e6, "G": 1e9, "T": 1e12, "P": 1e15, "E": 1e18,
+	"Ki": 1 << 10, "Mi": 1 << 20, "Gi": 1 << 30, "Ti": 1 << 40, "Pi": 1 << 50, "Ei": 1 << 60,
+}
+
+// kubernetesQuantityEqual reports whether desired and live denote the same resource quantity.
+func kubernetesQuantityEqual(desired interface{}, live string) bool {
+	a, ok := parseKubernetesQuantity(fmt.Sprint(desired))
+	if !ok {
+		return false
+	}
+	b, ok := parseKubernetesQuantity(live)
+	if !ok {
+		return false
+	}
+	return math.Abs(a-b) <= 1e-9*math.Max(math.Abs(a), math.Abs(b))
+}
+
+func parseKubernetesQuantity(q string) (float64, bool) {
+	i := len(q)
+	for i > 0 && (q[i-1] < '0' || q[i-1] > '9') && q[i-1] != '.' {
+		i--
+	}
+	multiplier, ok := kubernetesQuantitySuffixes[q[i:]]
+	if !ok || i == 0 {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(q[:i], 64)
+	if err != nil {
+		return 0, false
+	}
+	return n * multiplier, true
+}
+
+// kubernetesDriftNode renders live following the key order of the desired YAML node.
+func kubernetesDriftNode(desired *yaml.Node, live interface{}) *yaml.Node {
+	if desired.Kind == yaml.DocumentNode && len(desired.Content) == 1 {
+		return kubernetesDriftNode(desired.Content[0], live)
+	}
+
+	switch desired.Kind {
+	case yaml.MappingNode:
+		if lv, ok := live.(map[string]interface{}); ok {
+			out := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for i := 0; i+1 < len(desired.Content); i += 2 {
+				key := desired.Content[i]
+				if l, ok := lv[key.Value]; ok {
+					out.Content = append(out.Content,
+						&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.Value},
+						kubernetesDriftNode(desired.Content[i+1], l))
+				}
+			}
+			return out
+		}
+	case yaml.SequenceNode:
+		if lv, ok := live.([]interface{}); ok && len(lv) == len(desired.Content) {
+			out := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for i, l := range lv {
+				out.Content = append(out.Content, kubernetesDriftNode(desired.Content[i], l))
+			}
+			return out
+		}
+	}
+
+	var n yaml.Node
+	if err := n.Encode(live); err != nil {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(live)}
+	}
+	return &n
+}
+
+// secretStringDataToData folds Secret stringData into base64 data, the only form
+// returned by the API server.
+func secretStringDataToData(obj map[string]interface{}) map[string]interface{} {
+	if obj["kind"] != "Secret" {
+		return obj
+	}
+	stringData, ok := obj["stringData"].(map[string]interface{})
+	if !ok {
+		return obj
+	}
+
+	out := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		out[k] = v
+	}
+	data := map[string]interface{}{}
+	if existing, ok := obj["data"].(map[string]interface{}); ok {
+		for k, v := range existing {
+			data[k] = v
+		}
+	}
+	for k, v := range stringData {
+		data[k] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(v)))
+	}
+	out["data"] = data
+	delete(out, "stringData")
+	return out
+}
+
+// secretDataToStringData moves the live data of the keys a Secret manifest sets
+// in stringData back to stringData, so drift is rendered in the layout of the
+// manifest instead of being dropped. Live values are never decoded: a key that
+// still holds the configured value is rendered with it, any other key only
+// shows that it changed.
+func secretDataToStringData(desired, live map[string]interface{}) map[string]interface{} {
+	if desired["kind"] != "Secret" {
+		return live
+	}
+	stringData, ok := desired["stringData"].(map[string]interface{})
+	if !ok {
+		return live
+	}
+	liveData, _ := live["data"].(map[string]interface{})
+	desiredData, _ := desired["data"].(map[string]interface{})
+
+	out := make(map[string]interface{}, len(live))
+	for k, v := range live {
+		out[k] = v
+	}
+	data := map[string]interface{}{}
+	for k := range desiredData {
+		if v, ok := liveData[k]; ok {
+			data[k] = v
+		}
+	}
+	values := map[string]interface{}{}
+	for k, want := range stringData {
+		v, ok := liveData[k]
+		if !ok {
+			continue
+		}
+		if fmt.Sprint(v) == base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(want))) {
+			values[k] = want
+		} else {
+			values[k] = secretValueChanged
+		}
+	}
+
+	delete(out, "data")
+	if len(data) > 0 {
+		out["data"] = data
+	}
+	if len(values) > 0 {
+		out["stringData"] = values
+	}
+	return out
+}
+
+// toJSONValue converts a parsed manifest into the types produced by decoding JSON,
+// so it can be compared with objects returned by the API.
+func toJSONValue(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+// importKubernetesManifest fetches the live object at path and stores it as a
+// normalized YAML manifest in the "manifest" attribute.
+func importKubernetesManifest(d *schema.ResourceData, client *APIClient, path string) error {
+	obj, err := getKubernetesObject(client, path)
+	if err != nil {
+		return err
+	}
+	if obj == nil {
+		return fmt.Errorf("Kubernetes object %s not found", path)
+	}
+
+	normalizeKubernetesObject(obj)
+
+	manifest, err := renderKubernetesManifest(obj)
+	if err != nil {
+		return err
+	}
+	d.Set("manifest", manifest)
+	return nil
+}
+
+// renderKubernetesManifest encodes an object as YAML.
+func renderKubernetesManifest(obj map[string]interface{}) (string, error) {
+	out, err := encodeKubernetesYAML(obj)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return out, nil
+}
+
+// encodeKubernetesYAML encodes v as YAML with the two-space indentation used by kubectl.
+func encodeKubernetesYAML(v interface{}) (string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// normalizeKubernetesObject strips fields populated by the API server and
+// fields that only carry their default value, so the remaining object looks
+// like a manifest a user would write.
+func normalizeKubernetesObject(obj map[string]interface{}) {
+	delete(obj, "status")
+
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		stripKubernetesMetadata(metadata)
+	}
+
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	switch obj["kind"] {
+	case "Deployment":
+		deleteIfEqual(spec, "progressDeadlineSeconds", float64(600))
+		deleteIfEqual(spec, "revisionHistoryLimit", float64(10))
+		deleteIfEqual(spec, "strategy", map[string]interface{}{
+			"type": "RollingUpdate",
+			"rollingUpdate": map[string]interface{}{
+				"maxSurge":       "25%",
+				"maxUnavailable": "25%",
+			},
+		})
+	case "StatefulSet":
+		deleteIfEqual(spec, "podManagementPolicy", "OrderedReady")
+		deleteIfEqual(spec, "revisionHistoryLimit", float64(10))
+		deleteIfEqual(spec, "updateStrategy", map[string]interface{}{
+			"type":          "RollingUpdate",
+			"rollingUpdate": map[string]interface{}{"partition": float64(0)},
+		})
+		deleteIfEqual(spec, "persistentVolumeClaimRetentionPolicy", map[string]interface{}{
+			"whenDeleted": "Retain",
+			"whenScaled":  "Retain",
+		})
+	case "DaemonSet":
+		deleteIfEqual(spec, "revisionHistoryLimit", float64(10))
+		deleteIfEqual(spec, "updateStrategy", map[string]interface{}{
+			"type": "RollingUpdate",
+			"rollingUpdate": map[string]interface{}{
+				"maxSurge":       float64(0),
+				"maxUnavailable": float64(1),
+			},
+		})
+	case "Job":
+		stripKubernetesJobSpec(spec)
+	case "CronJob":
+		deleteIfEqual(spec, "concurrencyPolicy", "Allow")
+		deleteIfEqual(spec, "failedJobsHistoryLimit", float64(1))
+		deleteIfEqual(spec, "successfulJobsHistoryLimit", float64(3))
+		deleteIfEqual(spec, "suspend", false)
+		if jobTemplate, ok := spec["jobTemplate"].(map[string]interface{}); ok {
+			if metadata, ok := jobTemplate["metadata"].(map[string]interface{}); ok {
+				stripKubernetesMetadata(metadata)
+				if len(metadata) == 0 {
+					delete(jobTemplate, "metadata")
+				}
+			}
+			if jobSpec, ok := jobTemplate["spec"].(map[string]interface{}); ok {
+				stripKubernetesJobSpec(jobSpec)
+			}
+		}
+	case "Service":
+		delete(spec, "clusterIP")
+		delete(spec, "clusterIPs")
+		delete(spec, "ipFamilies")
+		deleteIfEqual(spec, "ipFamilyPolicy", "SingleStack")
+		deleteIfEqual(spec, "internalTrafficPolicy", "Cluster")
+		deleteIfEqual(spec, "sessionAffinity", "None")
+		deleteIfEqual(spec, "type", "ClusterIP")
+		if ports, ok := spec["ports"].([]interface{}); ok {
+			for _, p := range ports {
+				if port, ok := p.(map[string]interface{}); ok {
+					deleteIfEqual(port, "protocol", "TCP")
+				}
+			}
+		}
+	case "PersistentVolumeClaim":
+		deleteIfEqual(spec, "volumeMode", "Filesystem")
+		delete(spec, "volumeName")
+	case "PersistentVolume":
+		deleteIfEqual(spec, "volumeMode", "Filesystem")
+		delete(spec, "claimRef")
+	}
+
+	if template, ok := spec["template"].(map[string]interface{}); ok {
+		stripKubernetesPodTemplate(template)
+	}
+}
+
+func stripKubernetesJobSpec(spec map[string]interface{}) {
+	deleteIfEqual(spec, "backoffLimit", float64(6))
+	deleteIfEqual(spec, "completionMode", "NonIndexed")
+	deleteIfEqual(spec, "completions", float64(1))
+	deleteIfEqual(spec, "parallelism", float64(1))
+	deleteIfEqual(spec, "suspend", false)
+	deleteIfEqual(spec, "manualSelector", false)
+	deleteIfEqual(spec, "podReplacementPolicy", "TerminatingOrFailed")
+
+	// The selector and the matching template labels are generated by the
+	// Job controller unless manualSelector is set.
+	if _, manual := spec["manualSelector"]; !manual {
+		delete(spec, "selector")
+	}
+
+	if template, ok := spec["template"].(map[string]interface{}); ok {
+		if metadata, ok := template["metadata"].(map[string]interface{}); ok {
+			if labels, ok := metadata["labels"].(map[string]interface{}); ok {
+				for _, l := range []string{"controller-uid", "job-name", "batch.kubernetes.io/controller-uid", "batch.kubernetes.io/job-name"} {
+					delete(labels, l)
+				}
+				if len(labels) == 0 {
+					delete(metadata, "labels")
+				}
+			}
+		}
+		stripKubernetesPodTemplate(template)
+	}
+}
+
+func stripKubernetesPodTemplate(template map[string]interface{}) {
+	if metadata, ok := template["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+		if len(metadata) == 0 {
+			delete(template, "metadata")
+		}
+	}
+
+	spec, ok := template["spec"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	deleteIfEqual(spec, "dnsPolicy", "ClusterFirst")
+	deleteIfEqual(spec, "restartPolicy", "Always")
+	deleteIfEqual(spec, "schedulerName", "default-scheduler")
+	deleteIfEqual(spec, "securityContext", map[string]interface{}{})
+	deleteIfEqual(spec, "terminationGracePeriodSeconds", float64(30))
+
+	for _, key := range []string{"containers", "initContainers"} {
+		containers, ok := spec[key].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			deleteIfEqual(container, "terminationMessagePath", "/dev/termination-log")
+			deleteIfEqual(container, "terminationMessagePolicy", "File")
+			deleteIfEqual(container, "resources", map[string]interface{}{})
+			if ports, ok := container["ports"].([]interface{}); ok {
+				for _, p := range ports {
+					if port, ok := p.(map[string]interface{}); ok {
+						deleteIfEqual(port, "protocol", "TCP")
+					}
+				}
+			}
+		}
+	}
+}
+
+// stripKubernetesMetadata removes metadata fields managed by the API server.
+func stripKubernetesMetadata(metadata map[string]interface{}) {
+	for _, key := range []string{
+		"managedFields",
+		"resourceVersion",
+		"uid",
+		"creationTimestamp",
+		"generation",
+		"selfLink",
+		"deletionTimestamp",
+		"deletionGracePeriodSeconds",
+	} {
+		delete(metadata, key)
+	}
+
+	if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+		for _, key := range []string{
+			"kubectl.kubernetes.io/last-applied-configuration",
+			"deployment.kubernetes.io/revision",
+			"pv.kubernetes.io/bind-completed",
+			"pv.kubernetes.io/bound-by-controller",
+			"pv.kubernetes.io/provisioned-by",
+			"volume.beta.kubernetes.io/storage-provisioner",
+			"volume.kubernetes.io/storage-provisioner",
+		} {
+			delete(annotations, key)
+		}
+		if len(annotations) == 0 {
+			delete(metadata, "annotations")
+		}
+	}
+
+	if finalizers, ok := metadata["finalizers"].([]interface{}); ok {
+		kept := make([]interface{}, 0, len(finalizers))
+		for _, f := range finalizers {
+			if f != "kubernetes.io/pvc-protection" && f != "kubernetes.io/pv-protection" {
+				kept = append(kept, f)
+			}
+		}
+		if len(kept) == 0 {
+			delete(metadata, "finalizers")
+		} else {
+			metadata["finalizers"] = kept
+		}
+	}
+}
+
+func deleteIfEqual(m map[string]interface{}, key string, value interface{}) {
+	if v, ok := m[key]; ok && reflect.DeepEqual(v, value) {
+		delete(m, key)
+	}
+}
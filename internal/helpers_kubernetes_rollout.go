@@ -0,0 +1,225 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// waitForKubernetesRollout polls the workload at path until its rollout has
+// completed. On failure or timeout the error lists the waiting reasons of the
+// workload's containers and the warning events of its pods.
+func waitForKubernetesRollout(client *APIClient, endpointID int, namespace, path string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		obj, err := getKubernetesObject(client, path)
+		if err != nil {
+			return err
+		}
+		if obj == nil {
+			return fmt.Errorf("Kubernetes object %s not found while waiting for rollout", path)
+		}
+
+		done, progress, failure := kubernetesRolloutStatus(obj)
+		if done {
+			return nil
+		}
+		if failure != "" {
+			return fmt.Errorf("rollout of %s failed: %s%s", kubernetesObjectName(obj), failure,
+				kubernetesPodDiagnostics(client, endpointID, namespace, obj))
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("rollout of %s did not complete within %s: %s%s", kubernetesObjectName(obj), timeout, progress,
+				kubernetesPodDiagnostics(client, endpointID, namespace, obj))
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// kubernetesRolloutStatus reports whether the rollout of obj has completed. When
+// it has not, progress describes how far it got and failure is set if the
+// rollout can no longer succeed.
+func kubernetesRolloutStatus(obj map[string]interface{}) (done bool, progress string, failure string) {
+	spec, _ := obj["spec"].(map[string]interface{})
+	status, _ := obj["status"].(map[string]interface{})
+	metadata, _ := obj["metadata"].(map[string]interface{})
+
+	switch obj["kind"] {
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet":
+		if kubernetesInt(status, "observedGeneration") < kubernetesInt(metadata, "generation") {
+			return false, "the controller has not observed the latest generation yet", ""
+		}
+	}
+
+	replicas := int64(1)
+	if _, ok := spec["replicas"]; ok {
+		replicas = kubernetesInt(spec, "replicas")
+	}
+
+	switch obj["kind"] {
+	case "Deployment":
+		if cond := kubernetesCondition(status, "Progressing"); cond != nil && cond["reason"] == "ProgressDeadlineExceeded" {
+			return false, "", fmt.Sprint(cond["message"])
+		}
+		updated := kubernetesInt(status, "updatedReplicas")
+		available := kubernetesInt(status, "availableReplicas")
+		total := kubernetesInt(status, "replicas")
+		progress = fmt.Sprintf("%d of %d replicas updated, %d available", updated, replicas, available)
+		return updated >= replicas && total <= updated && available >= updated, progress, ""
+	case "StatefulSet":
+		strategy, _ := spec["updateStrategy"].(map[string]interface{})
+		if strategy["type"] == "OnDelete" {
+			return true, "", ""
+		}
+		// Pods below the partition keep the previous revision.
+		rollingUpdate, _ := strategy["rollingUpdate"].(map[string]interface{})
+		partition := kubernetesInt(rollingUpdate, "partition")
+		updated := kubernetesInt(status, "updatedReplicas")
+		ready := kubernetesInt(status, "readyReplicas")
+		progress = fmt.Sprintf("%d of %d replicas updated, %d ready", updated, replicas-partition, ready)
+		if partition == 0 && status["currentRevision"] != status["updateRevision"] {
+			return false, progress, ""
+		}
+		return updated >= replicas-partition && ready >= replicas, progress, ""
+	case "DaemonSet":
+		strategy, _ := spec["updateStrategy"].(map[string]interface{})
+		if strategy["type"] == "OnDelete" {
+			return true, "", ""
+		}
+		desired := kubernetesInt(status, "desiredNumberScheduled")
+		updated := kubernetesInt(status, "updatedNumberScheduled")
+		available := kubernetesInt(status, "numberAvailable")
+		progress = fmt.Sprintf("%d of %d pods updated, %d available", updated, desired, available)
+		return updated >= desired && available >= desired, progress, ""
+	case "ReplicaSet":
+		available := kubernetesInt(status, "availableReplicas")
+		progress = fmt.Sprintf("%d of %d replicas available", available, replicas)
+		return available >= replicas, progress, ""
+	case "Job":
+		if cond := kubernetesCondition(status, "Failed"); cond != nil && cond["status"] == "True" {
+			return false, "", fmt.Sprint(cond["message"])
+		}
+		if cond := kubernetesCondition(status, "Complete"); cond != nil && cond["status"] == "True" {
+			return true, "", ""
+		}
+		progress = fmt.Sprintf("%d pods active, %d succeeded, %d failed",
+			kubernetesInt(status, "active"), kubernetesInt(status, "succeeded"), kubernetesInt(status, "failed"))
+		return false, progress, ""
+	}
+	return true, "", ""
+}
+
+// kubernetesPodDiagnostics describes why the pods selected by obj are not ready:
+// container waiting or termination reasons and the latest warning events.
+func kubernetesPodDiagnostics(client *APIClient, endpointID int, namespace string, obj map[string]interface{}) string {
+	spec, _ := obj["spec"].(map[string]interface{})
+	selector, _ := spec["selector"].(map[string]interface{})
+	matchLabels, _ := selector["matchLabels"].(map[string]interface{})
+	if len(matchLabels) == 0 {
+		return ""
+	}
+
+	labels := make([]string, 0, len(matchLabels))
+	for k, v := range matchLabels {
+		labels = append(labels, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(labels)
+
+	base := fmt.Sprintf("/endpoints/%d/kubernetes/api/v1/namespaces/%s", endpointID, namespace)
+	pods, err := listKubernetesObjects(client, base+"/pods?labelSelector="+url.QueryEscape(strings.Join(labels, ",")))
+	if err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, pod := range pods {
+		podMetadata, _ := pod["metadata"].(map[string]interface{})
+		podName, _ := podMetadata["name"].(string)
+		status, _ := pod["status"].(map[string]interface{})
+		statuses, _ := status["initContainerStatuses"].([]interface{})
+		containers, _ := status["containerStatuses"].([]interface{})
+		for _, raw := range append(statuses, containers...) {
+			cs, _ := raw.(map[string]interface{})
+			state, _ := cs["state"].(map[string]interface{})
+			for _, phase := range []string{"waiting", "terminated"} {
+				s, ok := state[phase].(map[string]interface{})
+				if !ok || s["reason"] == nil || s["reason"] == "Completed" {
+					continue
+				}
+				fmt.Fprintf(&b, "\n  pod %s: container %v %s: %v", podName, cs["name"], phase, s["reason"])
+				if msg, ok := s["message"].(string); ok && msg != "" {
+					fmt.Fprintf(&b, " (%s)", msg)
+				}
+			}
+		}
+
+		events, err := listKubernetesObjects(client, base+"/events?fieldSelector="+url.QueryEscape("involvedObject.name="+podName))
+		if err != nil {
+			continue
+		}
+		var warnings []string
+		for _, event := range events {
+			if event["type"] == "Warning" {
+				warnings = append(warnings, fmt.Sprintf("\n  pod %s: event %v: %v", podName, event["reason"], event["message"]))
+			}
+		}
+		if len(warnings) > 3 {
+			warnings = warnings[len(warnings)-3:]
+		}
+		b.WriteString(strings.Join(warnings, ""))
+	}
+	return b.String()
+}
+
+// listKubernetesObjects returns the items of a Kubernetes list response.
+func listKubernetesObjects(client *APIClient, path string) ([]map[string]interface{}, error) {
+	resp, err := client.DoRequest(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to list Kubernetes objects (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var list struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// kubernetesCondition returns the condition of the given type from status.conditions.
+func kubernetesCondition(status map[string]interface{}, conditionType string) map[string]interface{} {
+	conditions, _ := status["conditions"].([]interface{})
+	for _, raw := range conditions {
+		if cond, ok := raw.(map[string]interface{}); ok && cond["type"] == conditionType {
+			return cond
+		}
+	}
+	return nil
+}
+
+// kubernetesInt returns the numeric field key of m, decoded from JSON as float64.
+func kubernetesInt(m map[string]interface{}, key string) int64 {
+	v, _ := m[key].(float64)
+	return int64(v)
+}
+
+func kubernetesObjectName(obj map[string]interface{}) string {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	if kind, ok := obj["kind"].(string); ok {
+		return strings.ToLower(kind) + " " + name
+	}
+	return name
+}
@@ -0,0 +1,295 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// stackCrashLoopRestarts is the number of restarts of a container, or failed
+// tasks of a service, after which a stack is considered crash looping.
+const stackCrashLoopRestarts = 3
+
+// waitForStackHealthy polls the containers of a standalone stack, or the
+// services of a swarm stack, until all of them are running and healthy. It gives
+// up before the timeout when a container exited with an error or a container or
+// service is crash looping. The error lists the failing containers or tasks with
+// their exit codes and last log lines.
+func waitForStackHealthy(client *APIClient, endpointID int, deployment, name string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		var healthy bool
+		var failures []stackHealthFailure
+		var err error
+		if deployment == "swarm" {
+			healthy, failures, err = swarmStackHealth(client, endpointID, name)
+		} else {
+			healthy, failures, err = composeStackHealth(client, endpointID, name)
+		}
+		if err != nil {
+			return err
+		}
+		if healthy {
+			return nil
+		}
+		for _, f := range failures {
+			if f.fatal {
+				return fmt.Errorf("stack %s failed to start%s", name, describeStackHealthFailures(client, failures))
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("stack %s did not become healthy within %s%s", name, timeout, describeStackHealthFailures(client, failures))
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// describeStackHealthFailures lists failures with the last log lines of each.
+func describeStackHealthFailures(client *APIClient, failures []stackHealthFailure) string {
+	var b strings.Builder
+	for _, f := range failures {
+		fmt.Fprintf(&b, "\n  %s: %s", f.name, f.reason)
+		for _, line := range dockerLogTail(client, f.logsPath) {
+			fmt.Fprintf(&b, "\n    | %s", line)
+		}
+	}
+	return b.String()
+}
+
+// stackHealthFailure describes a container or task that is not running and
+// healthy. A fatal failure will not recover by waiting longer.
+type stackHealthFailure struct {
+	name     string
+	reason   string
+	logsPath string
+	fatal    bool
+}
+
+// composeStackHealth checks the containers of the compose project of a standalone stack.
+func composeStackHealth(client *APIClient, endpointID int, name string) (bool, []stackHealthFailure, error) {
+	// docker compose normalizes project names to lower case.
+	filter := fmt.Sprintf(`{"label":["com.docker.compose.project=%s"]}`, strings.ToLower(name))
+	base := fmt.Sprintf("/endpoints/%d/docker", endpointID)
+
+	var containers []struct {
+		ID    string   `json:"Id"`
+		Names []string `json:"Names"`
+	}
+	if err := dockerGet(client, base+"/containers/json?all=1&filters="+url.QueryEscape(filter), &containers); err != nil {
+		return false, nil, fmt.Errorf("failed to list stack containers: %w", err)
+	}
+	if len(containers) == 0 {
+		return false, []stackHealthFailure{{name: name, reason: "no containers found"}}, nil
+	}
+
+	var failures []stackHealthFailure
+	for _, c := range containers {
+		var inspect struct {
+			RestartCount int `json:"RestartCount"`
+			State        struct {
+				Status   string `json:"Status"`
+				ExitCode int    `json:"ExitCode"`
+				Error    string `json:"Error"`
+				Health   *struct {
+					Status string `json:"Status"`
+				} `json:"Health"`
+			} `json:"State"`
+		}
+		if err := dockerGet(client, fmt.Sprintf("%s/containers/%s/json", base, c.ID), &inspect); err != nil {
+			return false, nil, fmt.Errorf("failed to inspect container %s: %w", c.ID, err)
+		}
+
+		state := inspect.State
+		// One-off containers that exited successfully are done.
+		if state.Status == "exited" && state.ExitCode == 0 && inspect.RestartCount == 0 {
+			continue
+		}
+
+		var reason string
+		fatal := false
+		switch {
+		case state.Status != "running":
+			fatal = state.Status == "exited" && state.ExitCode != 0 || inspect.RestartCount >= stackCrashLoopRestarts
+			reason = fmt.Sprintf("%s (exit code %d, %d restarts)", state.Status, state.ExitCode, inspect.RestartCount)
+			if state.Error != "" {
+				reason += ": " + state.Error
+			}
+		case state.Health != nil && state.Health.Status != "healthy":
+			reason = "health " + state.Health.Status
+		default:
+			continue
+		}
+
+		containerName := c.ID
+		if len(c.Names) > 0 {
+			containerName = strings.TrimPrefix(c.Names[0], "/")
+		}
+		failures = append(failures, stackHealthFailure{
+			name:     "container " + containerName,
+			reason:   reason,
+			logsPath: fmt.Sprintf("%s/containers/%s/logs?stdout=1&stderr=1&tail=5", base, c.ID),
+			fatal:    fatal,
+		})
+	}
+	return len(failures) == 0, failures, nil
+}
+
+// swarmStackHealth checks that every service of a swarm stack runs its desired
+// number of tasks. Swarm keeps tasks with a health check in the starting state
+// until they are healthy.
+func swarmStackHealth(client *APIClient, endpointID int, name string) (bool, []stackHealthFailure, error) {
+	filter := fmt.Sprintf(`{"label":["com.docker.stack.namespace=%s"]}`, name)
+	base := fmt.Sprintf("/endpoints/%d/docker", endpointID)
+
+	var services []struct {
+		ID        string    `json:"ID"`
+		UpdatedAt time.Time `json:"UpdatedAt"`
+		Spec      struct {
+			Name string `json:"Name"`
+			Mode struct {
+				Replicated *struct {
+					Replicas int `json:"Replicas"`
+				} `json:"Replicated"`
+				Global *struct{} `json:"Global"`
+			} `json:"Mode"`
+		} `json:"Spec"`
+	}
+	if err := dockerGet(client, base+"/services?filters="+url.QueryEscape(filter), &services); err != nil {
+		return false, nil, fmt.Errorf("failed to list stack services: %w", err)
+	}
+	if len(services) == 0 {
+		return false, []stackHealthFailure{{name: name, reason: "no services found"}}, nil
+	}
+
+	var failures []stackHealthFailure
+	for _, svc := range services {
+		var tasks []struct {
+			CreatedAt    time.Time `json:"CreatedAt"`
+			DesiredState string    `json:"DesiredState"`
+			Status       struct {
+				State           string `json:"State"`
+				Err             string `json:"Err"`
+				ContainerStatus *struct {
+					ExitCode int `json:"ExitCode"`
+				} `json:"ContainerStatus"`
+			} `json:"Status"`
+		}
+		taskFilter := fmt.Sprintf(`{"service":["%s"]}`, svc.ID)
+		if err := dockerGet(client, base+"/tasks?filters="+url.QueryEscape(taskFilter), &tasks); err != nil {
+			return false, nil, fmt.Errorf("failed to list tasks of service %s: %w", svc.Spec.Name, err)
+		}
+
+		running, desired, failed := 0, 0, 0
+		var lastError string
+		for _, t := range tasks {
+			if t.DesiredState == "running" {
+				desired++
+				if t.Status.State == "running" {
+					running++
+				}
+			}
+			if t.Status.State == "failed" || t.Status.State == "rejected" {
+				// Tasks that failed before the last deployment do not count.
+				if !t.CreatedAt.Before(svc.UpdatedAt) {
+					failed++
+				}
+				lastError = t.Status.State
+				if t.Status.ContainerStatus != nil {
+					lastError += fmt.Sprintf(" (exit code %d)", t.Status.ContainerStatus.ExitCode)
+				}
+				if t.Status.Err != "" {
+					lastError += ": " + t.Status.Err
+				}
+			}
+		}
+
+		// Global services run one task per eligible node, which only shows in the
+		// tasks swarm has scheduled.
+		if svc.Spec.Mode.Replicated != nil {
+			desired = svc.Spec.Mode.Replicated.Replicas
+		} else if desired == 0 {
+			desired = 1
+		}
+		if running >= desired {
+			continue
+		}
+
+		reason := fmt.Sprintf("%d of %d tasks running", running, desired)
+		if failed > 0 {
+			reason += fmt.Sprintf(", %d tasks failed", failed)
+		}
+		if lastError != "" {
+			reason += ", last task " + lastError
+		}
+		failures = append(failures, stackHealthFailure{
+			name:     "service " + svc.Spec.Name,
+			reason:   reason,
+			logsPath: fmt.Sprintf("%s/services/%s/logs?stdout=1&stderr=1&tail=5", base, svc.ID),
+			// Swarm replaces failed tasks, so only repeated failures are final.
+			fatal: failed >= stackCrashLoopRestarts,
+		})
+	}
+	return len(failures) == 0, failures, nil
+}
+
+// dockerGet decodes the JSON response of a GET through the Docker proxy into out.
+func dockerGet(client *APIClient, path string, out interface{}) error {
+	resp, err := client.DoRequest(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// dockerLogTail returns the log lines at path, or nothing if they cannot be read.
+func dockerLogTail(client *APIClient, path string) []string {
+	if path == "" {
+		return nil
+	}
+	resp, err := client.DoRequest(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != 200 {
+		return nil
+	}
+
+	text := strings.TrimRight(string(demuxDockerLogs(raw)), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// demuxDockerLogs strips the 8-byte stream headers Docker prefixes to each log
+// frame of a container without a TTY. Logs of TTY containers are returned as is.
+func demuxDockerLogs(raw []byte) []byte {
+	var out bytes.Buffer
+	for len(raw) >= 8 {
+		if raw[0] > 2 || raw[1] != 0 || raw[2] != 0 || raw[3] != 0 {
+			return append(out.Bytes(), raw...)
+		}
+		size := int(binary.BigEndian.Uint32(raw[4:8]))
+		if 8+size > len(raw) {
+			size = len(raw) - 8
+		}
+		out.Write(raw[8 : 8+size])
+		raw = raw[8+size:]
+	}
+	return append(out.Bytes(), raw...)
+}
@@ -94,6 +94,14 @@ func Provider() *schema.Provider {
 			"portainer_kubernetes_clusterrolebinding":           resourceKubernetesClusterRoleBindings(),
 			"portainer_kubernetes_volume":                       resourceKubernetesVolumes(),
 			"portainer_kubernetes_storage":                      resourceKubernetesStorage(),
+			"portainer_kubernetes_manifest":                     resourceKubernetesManifest(),
+			"portainer_kubernetes_kustomization":                resourceKubernetesKustomization(),
+			"portainer_helm_repository":                         resourceHelmRepository(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"portainer_edge_stack_status": dataSourceEdgeStackStatus(),
+			"portainer_edge_job_results":  dataSourceEdgeJobResults(),
+			"portainer_helm_charts":       dataSourceHelmCharts(),
 		},
 		ConfigureContextFunc: configureProvider,
 	}
@@ -9,7 +9,10 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -314,3 +317,84 @@ func resourceCustomTemplateDelete(d *schema.ResourceData, meta interface{}) erro
 	}
 	return nil
 }
+
+// customTemplateVariablePattern matches the variable placeholders of custom
+// templates, such as {{ .name }}.
+var customTemplateVariablePattern = regexp.MustCompile(`\{\{\s*\.?([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// fetchCustomTemplateFile returns the file of a custom template and the
+// default values of its variables.
+func fetchCustomTemplateFile(client *APIClient, templateID int) (string, map[string]string, error) {
+	resp, err := client.DoRequest("GET", fmt.Sprintf("/custom_templates/%d", templateID), nil, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read custom template %d: %w", templateID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return "", nil, fmt.Errorf("failed to read custom template %d: %s", templateID, string(data))
+	}
+
+	var template struct {
+		Variables []struct {
+			Name         string `json:"name"`
+			DefaultValue string `json:"defaultValue"`
+		} `json:"Variables"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&template); err != nil {
+		return "", nil, err
+	}
+	defaults := map[string]string{}
+	for _, v := range template.Variables {
+		if v.DefaultValue != "" {
+			defaults[v.Name] = v.DefaultValue
+		}
+	}
+
+	fileResp, err := client.DoRequest("GET", fmt.Sprintf("/custom_templates/%d/file", templateID), nil, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read file of custom template %d: %w", templateID, err)
+	}
+	defer fileResp.Body.Close()
+
+	if fileResp.StatusCode != 200 {
+		data, _ := io.ReadAll(fileResp.Body)
+		return "", nil, fmt.Errorf("failed to read file of custom template %d: %s", templateID, string(data))
+	}
+
+	var file struct {
+		FileContent string `json:"FileContent"`
+	}
+	if err := json.NewDecoder(fileResp.Body).Decode(&file); err != nil {
+		return "", nil, err
+	}
+	return file.FileContent, defaults, nil
+}
+
+// renderCustomTemplate replaces the variable placeholders in content with
+// values, falling back to defaults. Like Portainer, it fails when a variable
+// has neither.
+func renderCustomTemplate(content string, values, defaults map[string]string) (string, error) {
+	missing := map[string]bool{}
+	rendered := customTemplateVariablePattern.ReplaceAllStringFunc(content, func(match string) string {
+		name := customTemplateVariablePattern.FindStringSubmatch(match)[1]
+		if v, ok := values[name]; ok {
+			return v
+		}
+		if v, ok := defaults[name]; ok {
+			return v
+		}
+		missing[name] = true
+		return match
+	})
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("no value for template variables: %s", strings.Join(names, ", "))
+	}
+	return rendered, nil
+}
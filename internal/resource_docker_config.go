@@ -15,6 +15,9 @@ func resourceDockerConfig() *schema.Resource {
 		Read:   resourceDockerConfigRead,
 		Update: resourceDockerConfigUpdate,
 		Delete: resourceDockerConfigDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceDockerConfigImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"endpoint_id": {
 				Type:     schema.TypeInt,
@@ -39,6 +42,7 @@ func resourceDockerConfig() *schema.Resource {
 				Optional: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"access_control": accessControlSchema(),
 		},
 	}
 }
@@ -82,11 +86,59 @@ func resourceDockerConfigCreate(d *schema.ResourceData, meta interface{}) error
 	}
 
 	d.SetId(response.ID)
-	return nil
+	return updateDockerAccessControl(d, client, fmt.Sprintf("/endpoints/%d/docker/configs/%s", endpointID, response.ID), response.ID, resourceControlTypeConfig)
 }
 
 func resourceDockerConfigRead(d *schema.ResourceData, meta interface{}) error {
-	return nil
+	client := meta.(*APIClient)
+	endpointID := d.Get("endpoint_id").(int)
+
+	path := fmt.Sprintf("/endpoints/%d/docker/configs/%s", endpointID, d.Id())
+	resp, err := client.DoRequest(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read docker config: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		d.SetId("")
+		return nil
+	} else if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to read docker config: %s", string(body))
+	}
+
+	var config struct {
+		portainerDecoration
+		Spec struct {
+			Name       string            `json:"Name"`
+			Labels     map[string]string `json:"Labels"`
+			Data       string            `json:"Data"`
+			Templating *dockerDriver     `json:"Templating"`
+		} `json:"Spec"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
+		return err
+	}
+
+	d.Set("name", config.Spec.Name)
+	d.Set("labels", config.Spec.Labels)
+	d.Set("templating", config.Spec.Templating.toMap())
+	// Data is sent to Docker as-is (base64) and returned in the same form.
+	d.Set("data", config.Spec.Data)
+	return setAccessControl(d, config.resourceControl())
+}
+
+// resourceDockerConfigImport accepts "<endpointId>:<configId>".
+func resourceDockerConfigImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	endpointID, configID, err := parseEndpointScopedImportID(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("%w, expected <endpointId>:<configId>", err)
+	}
+
+	d.Set("endpoint_id", endpointID)
+	d.SetId(configID)
+	return []*schema.ResourceData{d}, nil
 }
 
 func resourceDockerConfigDelete(d *schema.ResourceData, meta interface{}) error {
@@ -111,6 +163,16 @@ func resourceDockerConfigDelete(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceDockerConfigUpdate(d *schema.ResourceData, meta interface{}) error {
+	// Docker configs are immutable, but their access control is kept by Portainer.
+	if !d.HasChangeExcept("access_control") {
+		client := meta.(*APIClient)
+		path := fmt.Sprintf("/endpoints/%d/docker/configs/%s", d.Get("endpoint_id").(int), d.Id())
+		if err := updateDockerAccessControl(d, client, path, d.Id(), resourceControlTypeConfig); err != nil {
+			return err
+		}
+		return resourceDockerConfigRead(d, meta)
+	}
+
 	if err := resourceDockerConfigDelete(d, meta); err != nil {
 		return fmt.Errorf("failed to delete docker config during update: %w", err)
 	}
@@ -25,6 +25,9 @@ func resourceDockerImage() *schema.Resource {
 		Read:   resourceDockerImageRead,
 		Delete: resourceDockerImageDelete,
 		Update: nil,
+		Importer: &schema.ResourceImporter{
+			State: resourceDockerImageImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"endpoint_id":   {Type: schema.TypeInt, Required: true, ForceNew: true},
 			"image":         {Type: schema.TypeString, Required: true, ForceNew: true},
@@ -83,9 +86,42 @@ func resourceDockerImageCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceDockerImageRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+
+	endpointID := d.Get("endpoint_id").(int)
+	image := d.Get("image").(string)
+
+	path := fmt.Sprintf("/endpoints/%d/docker/images/%s/json", endpointID, url.PathEscape(image))
+	resp, err := client.DoRequest(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		d.SetId("")
+		return nil
+	} else if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to inspect image, status code: %d, body: %s", resp.StatusCode, string(body))
+	}
+
 	return nil
 }
 
+// resourceDockerImageImport accepts "<endpointId>:<image>", e.g. "1:nginx:latest".
+func resourceDockerImageImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	endpointID, image, err := parseEndpointScopedImportID(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("%w, expected <endpointId>:<image>", err)
+	}
+
+	d.Set("endpoint_id", endpointID)
+	d.Set("image", image)
+	d.SetId(fmt.Sprintf("%d-%s", endpointID, image))
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceDockerImageDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*APIClient)
 
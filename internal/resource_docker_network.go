@@ -14,12 +14,15 @@ func resourceDockerNetwork() *schema.Resource {
 		Create: resourceDockerNetworkCreate,
 		Read:   resourceDockerNetworkRead,
 		Delete: resourceDockerNetworkDelete,
-		Update: nil,
+		Update: resourceDockerNetworkUpdate,
+		Importer: &schema.ResourceImporter{
+			State: resourceDockerNetworkImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"endpoint_id": {Type: schema.TypeInt, Required: true, ForceNew: true},
 			"name":        {Type: schema.TypeString, Required: true, ForceNew: true},
 			"driver":      {Type: schema.TypeString, Optional: true, Default: "bridge", ForceNew: true},
-			"scope":       {Type: schema.TypeString, Optional: true, ForceNew: true},
+			"scope":       {Type: schema.TypeString, Optional: true, Computed: true, ForceNew: true},
 			"internal":    {Type: schema.TypeBool, Optional: true, Default: false, ForceNew: true},
 			"attachable":  {Type: schema.TypeBool, Optional: true, Default: false, ForceNew: true},
 			"ingress":     {Type: schema.TypeBool, Optional: true, Default: false, ForceNew: true},
@@ -27,8 +30,10 @@ func resourceDockerNetwork() *schema.Resource {
 			"config_from": {Type: schema.TypeString, Optional: true, ForceNew: true},
 			"enable_ipv4": {Type: schema.TypeBool, Optional: true, Default: true, ForceNew: true},
 			"enable_ipv6": {Type: schema.TypeBool, Optional: true, Default: false, ForceNew: true},
-			"options":     {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}, ForceNew: true},
+			"options":     {Type: schema.TypeMap, Optional: true, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}, ForceNew: true},
 			"labels":      {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}, ForceNew: true},
+
+			"access_control": accessControlSchema(),
 		},
 	}
 }
@@ -78,11 +83,89 @@ func resourceDockerNetworkCreate(d *schema.ResourceData, meta interface{}) error
 	}
 
 	d.SetId(response.ID)
-	return nil
+	return updateDockerAccessControl(d, client, fmt.Sprintf("/endpoints/%d/docker/networks/%s", endpointID, response.ID), response.ID, resourceControlTypeNetwork)
 }
 
 func resourceDockerNetworkRead(d *schema.ResourceData, meta interface{}) error {
-	return nil
+	client := meta.(*APIClient)
+	endpointID := d.Get("endpoint_id").(int)
+
+	path := fmt.Sprintf("/endpoints/%d/docker/networks/%s", endpointID, d.Id())
+	resp, err := client.DoRequest(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read docker network: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		d.SetId("")
+		return nil
+	} else if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to read docker network: %s", string(body))
+	}
+
+	var network struct {
+		portainerDecoration
+		Name       string            `json:"Name"`
+		Driver     string            `json:"Driver"`
+		Scope      string            `json:"Scope"`
+		Internal   bool              `json:"Internal"`
+		Attachable bool              `json:"Attachable"`
+		Ingress    bool              `json:"Ingress"`
+		ConfigOnly bool              `json:"ConfigOnly"`
+		EnableIPv4 *bool             `json:"EnableIPv4"`
+		EnableIPv6 bool              `json:"EnableIPv6"`
+		Options    map[string]string `json:"Options"`
+		Labels     map[string]string `json:"Labels"`
+		ConfigFrom struct {
+			Network string `json:"Network"`
+		} `json:"ConfigFrom"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&network); err != nil {
+		return err
+	}
+
+	d.Set("name", network.Name)
+	d.Set("driver", network.Driver)
+	d.Set("scope", network.Scope)
+	d.Set("internal", network.Internal)
+	d.Set("attachable", network.Attachable)
+	d.Set("ingress", network.Ingress)
+	d.Set("config_only", network.ConfigOnly)
+	d.Set("config_from", network.ConfigFrom.Network)
+	d.Set("enable_ipv6", network.EnableIPv6)
+	// Older Docker engines do not report EnableIPv4; IPv4 is always enabled there.
+	if network.EnableIPv4 != nil {
+		d.Set("enable_ipv4", *network.EnableIPv4)
+	} else {
+		d.Set("enable_ipv4", true)
+	}
+	d.Set("options", network.Options)
+	d.Set("labels", network.Labels)
+
+	return setAccessControl(d, network.resourceControl())
+}
+
+func resourceDockerNetworkUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+	path := fmt.Sprintf("/endpoints/%d/docker/networks/%s", d.Get("endpoint_id").(int), d.Id())
+	if err := updateDockerAccessControl(d, client, path, d.Id(), resourceControlTypeNetwork); err != nil {
+		return err
+	}
+	return resourceDockerNetworkRead(d, meta)
+}
+
+// resourceDockerNetworkImport accepts "<endpointId>:<networkId>".
+func resourceDockerNetworkImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	endpointID, networkID, err := parseEndpointScopedImportID(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("%w, expected <endpointId>:<networkId>", err)
+	}
+
+	d.Set("endpoint_id", endpointID)
+	d.SetId(networkID)
+	return []*schema.ResourceData{d}, nil
 }
 
 func resourceDockerNetworkDelete(d *schema.ResourceData, meta interface{}) error {
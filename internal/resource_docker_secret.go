@@ -15,11 +15,22 @@ func resourceDockerSecret() *schema.Resource {
 		Read:   resourceDockerSecretRead,
 		Delete: resourceDockerSecretDelete,
 		Update: resourceDockerSecretUpdate,
+		Importer: &schema.ResourceImporter{
+			State: resourceDockerSecretImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"endpoint_id": {Type: schema.TypeInt, Required: true},
 			"name":        {Type: schema.TypeString, Required: true},
-			"data":        {Type: schema.TypeString, Required: true, Sensitive: true},
-			"labels":      {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"data": {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+				// Docker never returns secret data, so it is unknown after import.
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return old == "" && d.Id() != ""
+				},
+			},
+			"labels": {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
 			"driver": {
 				Type:     schema.TypeMap,
 				Optional: true,
@@ -30,6 +41,7 @@ func resourceDockerSecret() *schema.Resource {
 				Optional: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"access_control": accessControlSchema(),
 		},
 	}
 }
@@ -79,14 +91,105 @@ func resourceDockerSecretCreate(d *schema.ResourceData, meta interface{}) error
 	}
 
 	d.SetId(response.ID)
-	return nil
+	return updateDockerAccessControl(d, client, fmt.Sprintf("/endpoints/%d/docker/secrets/%s", endpointID, response.ID), response.ID, resourceControlTypeSecret)
 }
 
 func resourceDockerSecretRead(d *schema.ResourceData, meta interface{}) error {
-	return nil
+	client := meta.(*APIClient)
+	endpointID := d.Get("endpoint_id").(int)
+
+	path := fmt.Sprintf("/endpoints/%d/docker/secrets/%s", endpointID, d.Id())
+	resp, err := client.DoRequest(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read docker secret: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		d.SetId("")
+		return nil
+	} else if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to read docker secret: %s", string(body))
+	}
+
+	var secret struct {
+		portainerDecoration
+		Spec struct {
+			Name       string            `json:"Name"`
+			Labels     map[string]string `json:"Labels"`
+			Driver     *dockerDriver     `json:"Driver"`
+			Templating *dockerDriver     `json:"Templating"`
+		} `json:"Spec"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return err
+	}
+
+	// The secret data is never returned by Docker, so "data" is kept from state.
+	d.Set("name", secret.Spec.Name)
+	d.Set("labels", secret.Spec.Labels)
+	d.Set("driver", secret.Spec.Driver.toMap())
+	d.Set("templating", secret.Spec.Templating.toMap())
+	return setAccessControl(d, secret.resourceControl())
+}
+
+// resourceDockerSecretImport accepts "<endpointId>:<secretId>".
+func resourceDockerSecretImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	endpointID, secretID, err := parseEndpointScopedImportID(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("%w, expected <endpointId>:<secretId>", err)
+	}
+
+	d.Set("endpoint_id", endpointID)
+	d.SetId(secretID)
+	return []*schema.ResourceData{d}, nil
+}
+
+// dockerDriver is the driver/templating object of Docker secrets and configs.
+type dockerDriver struct {
+	Name    string            `json:"Name"`
+	Options map[string]string `json:"Options"`
+}
+
+// toMap flattens the driver back into the schema form, where "name" is
+// stored alongside the options.
+func (dd *dockerDriver) toMap() map[string]string {
+	if dd == nil {
+		return nil
+	}
+	out := map[string]string{}
+	for k, v := range dd.Options {
+		out[k] = v
+	}
+	if dd.Name != "" {
+		out["name"] = dd.Name
+	}
+	return out
 }
 
 func resourceDockerSecretUpdate(d *schema.ResourceData, meta interface{}) error {
+	// Docker secrets are immutable, but their access control is kept by Portainer.
+	if !d.HasChangeExcept("access_control") {
+		client := meta.(*APIClient)
+		path := fmt.Sprintf("/endpoints/%d/docker/secrets/%s", d.Get("endpoint_id").(int), d.Id())
+		if err := updateDockerAccessControl(d, client, path, d.Id(), resourceControlTypeSecret); err != nil {
+			return err
+		}
+		return resourceDockerSecretRead(d, meta)
+	}
+
+	// After import the diff of data is suppressed, so the plan still holds the
+	// empty imported value. Recreate the secret with the configured data instead.
+	if d.Get("data").(string) == "" {
+		if v := d.GetRawConfig().GetAttr("data"); v.IsKnown() && !v.IsNull() {
+			d.Set("data", v.AsString())
+		}
+		if d.Get("data").(string) == "" {
+			return fmt.Errorf("refusing to recreate docker secret %s without data", d.Id())
+		}
+	}
+
 	if err := resourceDockerSecretDelete(d, meta); err != nil {
 		return fmt.Errorf("failed to delete docker secret during update: %w", err)
 	}
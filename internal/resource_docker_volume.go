@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -21,7 +22,10 @@ func resourceDockerVolume() *schema.Resource {
 		Create: resourceDockerVolumeCreate,
 		Read:   resourceDockerVolumeRead,
 		Delete: resourceDockerVolumeDelete,
-		Update: nil,
+		Update: resourceDockerVolumeUpdate,
+		Importer: &schema.ResourceImporter{
+			State: resourceDockerVolumeImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"endpoint_id": {
 				Type:     schema.TypeInt,
@@ -51,6 +55,7 @@ func resourceDockerVolume() *schema.Resource {
 				ForceNew: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"access_control": accessControlSchema(),
 		},
 	}
 }
@@ -79,12 +84,68 @@ func resourceDockerVolumeCreate(d *schema.ResourceData, meta interface{}) error
 	}
 
 	d.SetId(fmt.Sprintf("%d-%s", endpointID, volume.Name))
-	return nil
+	return updateDockerAccessControl(d, client, fmt.Sprintf("/endpoints/%d/docker/volumes/%s", endpointID, url.PathEscape(volume.Name)), volume.Name, resourceControlTypeVolume)
 }
 
 func resourceDockerVolumeRead(d *schema.ResourceData, meta interface{}) error {
-	// Stateless
-	return nil
+	client := meta.(*APIClient)
+	endpointID := d.Get("endpoint_id").(int)
+	name := d.Get("name").(string)
+
+	path := fmt.Sprintf("/endpoints/%d/docker/volumes/%s", endpointID, url.PathEscape(name))
+	resp, err := client.DoRequest(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read volume: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		d.SetId("")
+		return nil
+	} else if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to read volume, status code: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var volume struct {
+		portainerDecoration
+		Name    string            `json:"Name"`
+		Driver  string            `json:"Driver"`
+		Options map[string]string `json:"Options"`
+		Labels  map[string]string `json:"Labels"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&volume); err != nil {
+		return err
+	}
+
+	d.Set("name", volume.Name)
+	d.Set("driver", volume.Driver)
+	d.Set("driver_opts", volume.Options)
+	d.Set("labels", volume.Labels)
+	return setAccessControl(d, volume.resourceControl())
+}
+
+func resourceDockerVolumeUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+	name := d.Get("name").(string)
+	path := fmt.Sprintf("/endpoints/%d/docker/volumes/%s", d.Get("endpoint_id").(int), url.PathEscape(name))
+	if err := updateDockerAccessControl(d, client, path, name, resourceControlTypeVolume); err != nil {
+		return err
+	}
+	return resourceDockerVolumeRead(d, meta)
+}
+
+// resourceDockerVolumeImport accepts "<endpointId>:<volumeName>".
+func resourceDockerVolumeImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	endpointID, name, err := parseEndpointScopedImportID(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("%w, expected <endpointId>:<volumeName>", err)
+	}
+
+	d.Set("endpoint_id", endpointID)
+	d.Set("name", name)
+	d.SetId(fmt.Sprintf("%d-%s", endpointID, name))
+	return []*schema.ResourceData{d}, nil
 }
 
 func resourceDockerVolumeDelete(d *schema.ResourceData, meta interface{}) error {
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -21,6 +22,9 @@ func resourceEdgeJob() *schema.Resource {
 		Read:   resourceEdgeJobRead,
 		Update: resourceEdgeJobUpdate,
 		Delete: resourceEdgeJobDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -170,7 +174,76 @@ func resourceEdgeJobCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceEdgeJobRead(d *schema.ResourceData, meta interface{}) error {
-	// Optional: implement if needed
+	client := meta.(*APIClient)
+
+	resp, err := client.DoRequest("GET", fmt.Sprintf("/edge_jobs/%s", d.Id()), nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		d.SetId("")
+		return nil
+	} else if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to read edge job: %s", string(data))
+	}
+
+	var job struct {
+		Name           string                     `json:"Name"`
+		CronExpression string                     `json:"CronExpression"`
+		Recurring      bool                       `json:"Recurring"`
+		EdgeGroups     []int                      `json:"EdgeGroups"`
+		Endpoints      map[string]json.RawMessage `json:"Endpoints"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return err
+	}
+
+	endpoints := make([]int, 0, len(job.Endpoints))
+	for k := range job.Endpoints {
+		if id, err := strconv.Atoi(k); err == nil {
+			endpoints = append(endpoints, id)
+		}
+	}
+	sort.Ints(endpoints)
+
+	d.Set("name", job.Name)
+	d.Set("cron_expression", job.CronExpression)
+	d.Set("recurring", job.Recurring)
+
+	// The API does not preserve the configured order of these lists.
+	if !sameIntSet(job.EdgeGroups, toIntSlice(d.Get("edge_groups").([]interface{}))) {
+		d.Set("edge_groups", job.EdgeGroups)
+	}
+	if !sameIntSet(endpoints, toIntSlice(d.Get("endpoints").([]interface{}))) {
+		d.Set("endpoints", endpoints)
+	}
+
+	// Jobs created from a local file keep tracking the file, not its content.
+	if _, ok := d.GetOk("file_path"); ok {
+		return nil
+	}
+
+	fileResp, err := client.DoRequest("GET", fmt.Sprintf("/edge_jobs/%s/file", d.Id()), nil, nil)
+	if err != nil {
+		return err
+	}
+	defer fileResp.Body.Close()
+
+	if fileResp.StatusCode != 200 {
+		data, _ := io.ReadAll(fileResp.Body)
+		return fmt.Errorf("failed to read edge job file: %s", string(data))
+	}
+
+	var file struct {
+		FileContent string `json:"FileContent"`
+	}
+	if err := json.NewDecoder(fileResp.Body).Decode(&file); err != nil {
+		return err
+	}
+	d.Set("file_content", file.FileContent)
 	return nil
 }
 
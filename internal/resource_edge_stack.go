@@ -2,6 +2,7 @@ package internal
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,8 +10,11 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -20,6 +24,13 @@ func resourceEdgeStack() *schema.Resource {
 		Read:   resourceEdgeStackRead,
 		Delete: resourceEdgeStackDelete,
 		Update: resourceEdgeStackUpdate,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: resourceEdgeStackCustomizeDiff,
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
+			validateEdgeStackComposeFile,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -27,8 +38,9 @@ func resourceEdgeStack() *schema.Resource {
 				Required: true,
 			},
 			"stack_file_content": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentYAML,
 			},
 			"stack_file_path": {
 				Type:     schema.TypeString,
@@ -83,10 +95,86 @@ func resourceEdgeStack() *schema.Resource {
 				Optional: true,
 				Default:  false,
 			},
+
+			"env_file_content":        envFileContentSchema(),
+			"sensitive_env":           sensitiveEnvSchema(),
+			"sensitive_env_revisions": sensitiveEnvRevisionsSchema(),
 		},
 	}
 }
 
+// resourceEdgeStackCustomizeDiff validates Docker Compose edge stacks at plan time.
+func resourceEdgeStackCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := sensitiveEnvRevisionsCustomizeDiff(ctx, d, meta); err != nil {
+		return err
+	}
+	if d.Get("deployment_type").(int) != 0 {
+		return nil
+	}
+
+	if !d.NewValueKnown("stack_file_content") {
+		return nil
+	}
+	attr := "stack_file_content"
+	content := d.Get("stack_file_content").(string)
+	if content == "" {
+		path := d.Get("stack_file_path").(string)
+		if path == "" || !d.NewValueKnown("stack_file_path") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read stack_file_path: %w", err)
+		}
+		attr, content = "stack_file_path", string(data)
+	}
+
+	var env map[string]string
+	if d.NewValueKnown("env_file_content") && d.NewValueKnown("sensitive_env") {
+		env = map[string]string{}
+		for _, e := range mergeEnvSources(d.Get("env_file_content").(string), nil, d.Get("sensitive_env").(map[string]interface{})) {
+			env[e["name"]] = e["value"]
+		}
+	}
+
+	// Warnings are reported by validateEdgeStackComposeFile.
+	if _, err := validateComposeFile(content, env, false); err != nil {
+		return fmt.Errorf("%s is not a valid compose file: %w", attr, err)
+	}
+	return nil
+}
+
+// validateEdgeStackComposeFile warns about variables of a Docker Compose edge
+// stack that no env source sets.
+func validateEdgeStackComposeFile(ctx context.Context, req schema.ValidateResourceConfigFuncRequest, resp *schema.ValidateResourceConfigFuncResponse) {
+	config := req.RawConfig
+	if config.IsNull() || !config.IsKnown() {
+		return
+	}
+	if deployment := config.GetAttr("deployment_type"); !deployment.IsKnown() || deployment.IsNull() || !deployment.RawEquals(cty.NumberIntVal(0)) {
+		return
+	}
+
+	attr, content := "stack_file_content", rawConfigString(config.GetAttr("stack_file_content"))
+	if content == "" {
+		path := rawConfigString(config.GetAttr("stack_file_path"))
+		if path == "" {
+			return
+		}
+		// An unreadable file is reported by resourceEdgeStackCustomizeDiff.
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return
+		}
+		attr, content = "stack_file_path", string(data)
+	}
+	if content == "" {
+		return
+	}
+
+	resp.Diagnostics = append(resp.Diagnostics, composeWarningDiagnostics(attr, content, rawConfigEnv(config), false)...)
+}
+
 func resourceEdgeStackCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*APIClient)
 
@@ -95,6 +183,7 @@ func resourceEdgeStackCreate(d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	deployType := d.Get("deployment_type").(int)
 	useManifest := d.Get("use_manifest_namespaces").(bool)
+	envVars := edgeStackEnv(d)
 
 	// Method: stackFileContent (string)
 	if content, ok := d.GetOk("stack_file_content"); ok {
@@ -105,6 +194,7 @@ func resourceEdgeStackCreate(d *schema.ResourceData, meta interface{}) error {
 			"stackFileContent":      content.(string),
 			"useManifestNamespaces": useManifest,
 			"registries":            registries,
+			"envVars":               envVars,
 		}
 		return createEdgeStackFromJSON(client, d, payload, "/edge_stacks/create/string")
 	}
@@ -126,6 +216,7 @@ func resourceEdgeStackCreate(d *schema.ResourceData, meta interface{}) error {
 		_ = writer.WriteField("EdgeGroups", toJSONString(edgeGroups))
 		_ = writer.WriteField("UseManifestNamespaces", strconv.FormatBool(useManifest))
 		_ = writer.WriteField("Registries", toJSONString(registries))
+		_ = writer.WriteField("EnvVars", toJSONString(envVars))
 
 		part, err := writer.CreateFormFile("file", filepath.Base(filePath))
 		if err != nil {
@@ -171,6 +262,7 @@ func resourceEdgeStackCreate(d *schema.ResourceData, meta interface{}) error {
 			"filePathInRepository":    d.Get("file_path_in_repository").(string),
 			"useManifestNamespaces":   useManifest,
 			"registries":              registries,
+			"envVars":                 envVars,
 		}
 		return createEdgeStackFromJSON(client, d, payload, "/edge_stacks/create/repository")
 	}
@@ -187,6 +279,7 @@ func resourceEdgeStackUpdate(d *schema.ResourceData, meta interface{}) error {
 		"edgeGroups":            toIntSlice(d.Get("edge_groups").([]interface{})),
 		"updateVersion":         true,
 		"useManifestNamespaces": d.Get("use_manifest_namespaces").(bool),
+		"envVars":               edgeStackEnv(d),
 	}
 
 	if v, ok := d.GetOk("stack_file_content"); ok {
@@ -251,6 +344,11 @@ func createEdgeStackFromJSON(client *APIClient, d *schema.ResourceData, payload
 	return resourceEdgeStackRead(d, client)
 }
 
+// edgeStackEnv returns the variables of env_file_content and sensitive_env for the envVars payload.
+func edgeStackEnv(d *schema.ResourceData) []map[string]string {
+	return mergeEnvSources(d.Get("env_file_content").(string), nil, d.Get("sensitive_env").(map[string]interface{}))
+}
+
 func toIntSlice(input []interface{}) []int {
 	out := make([]int, len(input))
 	for i, v := range input {
@@ -285,15 +383,112 @@ func resourceEdgeStackRead(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	var stack struct {
-		Name string `json:"Name"`
+		Name                  string `json:"Name"`
+		DeploymentType        int    `json:"DeploymentType"`
+		EdgeGroups            []int  `json:"EdgeGroups"`
+		Registries            []int  `json:"Registries"`
+		UseManifestNamespaces bool   `json:"UseManifestNamespaces"`
+		GitConfig             *struct {
+			URL            string `json:"URL"`
+			ReferenceName  string `json:"ReferenceName"`
+			ConfigFilePath string `json:"ConfigFilePath"`
+			Authentication *struct {
+				Username string `json:"Username"`
+			} `json:"Authentication"`
+		} `json:"GitConfig"`
+		EnvVars []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"EnvVars"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&stack); err != nil {
 		return err
 	}
 	d.Set("name", stack.Name)
+	d.Set("deployment_type", stack.DeploymentType)
+	d.Set("use_manifest_namespaces", stack.UseManifestNamespaces)
+
+	actual := make([]map[string]string, 0, len(stack.EnvVars))
+	for _, e := range stack.EnvVars {
+		actual = append(actual, map[string]string{"name": e.Name, "value": e.Value})
+	}
+	// Edge stacks have no env blocks, so variables set outside of Terraform
+	// end up in the dotenv content.
+	envFile, extra, sensitive := splitEnvSources(actual, d.Get("env_file_content").(string), nil,
+		d.Get("sensitive_env").(map[string]interface{}))
+	if len(extra) > 0 {
+		lines := strings.TrimRight(envFile, "\n")
+		for _, e := range extra {
+			lines += "\n" + e["name"].(string) + "=" + formatDotenvValue(e["value"].(string))
+		}
+		envFile = strings.TrimLeft(lines, "\n") + "\n"
+	}
+	d.Set("env_file_content", envFile)
+	if err := d.Set("sensitive_env", sensitive); err != nil {
+		return fmt.Errorf("failed to set sensitive_env: %w", err)
+	}
+
+	// The API does not preserve the configured order of these lists.
+	if !sameIntSet(stack.EdgeGroups, toIntSlice(d.Get("edge_groups").([]interface{}))) {
+		d.Set("edge_groups", stack.EdgeGroups)
+	}
+	if !sameIntSet(stack.Registries, toIntSlice(d.Get("registries").([]interface{}))) {
+		d.Set("registries", stack.Registries)
+	}
+
+	if stack.GitConfig != nil {
+		d.Set("repository_url", stack.GitConfig.URL)
+		d.Set("repository_reference_name", stack.GitConfig.ReferenceName)
+		d.Set("file_path_in_repository", stack.GitConfig.ConfigFilePath)
+		if stack.GitConfig.Authentication != nil {
+			d.Set("repository_username", stack.GitConfig.Authentication.Username)
+		}
+		return nil
+	}
+
+	// Stacks created from a local file keep tracking the file, not its content.
+	if _, ok := d.GetOk("stack_file_path"); ok {
+		return nil
+	}
+
+	fileResp, err := client.DoRequest("GET", fmt.Sprintf("/edge_stacks/%s/file", d.Id()), nil, nil)
+	if err != nil {
+		return err
+	}
+	defer fileResp.Body.Close()
+
+	if fileResp.StatusCode != 200 {
+		data, _ := io.ReadAll(fileResp.Body)
+		return fmt.Errorf("failed to read edge stack file: %s", string(data))
+	}
+
+	var file struct {
+		StackFileContent string `json:"StackFileContent"`
+	}
+	if err := json.NewDecoder(fileResp.Body).Decode(&file); err != nil {
+		return err
+	}
+	d.Set("stack_file_content", file.StackFileContent)
 	return nil
 }
 
+// sameIntSet reports whether a and b contain the same elements, ignoring order.
+func sameIntSet(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	x := append([]int(nil), a...)
+	y := append([]int(nil), b...)
+	sort.Ints(x)
+	sort.Ints(y)
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func resourceEdgeStackDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*APIClient)
 
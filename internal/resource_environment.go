@@ -18,6 +18,9 @@ func resourceEnvironment() *schema.Resource {
 		Read:   resourceEnvironmentRead,
 		Delete: resourceEnvironmentDelete,
 		Update: resourceEnvironmentUpdate,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -53,24 +56,35 @@ func resourceEnvironment() *schema.Resource {
 				Description: "List of tag IDs to assign to the environment.",
 			},
 			"tls_enabled": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  true,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Use TLS to connect to the environment; read from Portainer when unset",
 			},
 			"tls_skip_verify": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  true,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Skip verification of the environment's TLS certificate; read from Portainer when unset",
 			},
 			"tls_skip_client_verify": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  true,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Skip TLS client verification; Portainer does not report this setting",
 			},
 		},
 	}
 }
 
+// environmentTLSFields maps the TLS attributes to the fields of the create
+// (multipart) and update (JSON) requests.
+var environmentTLSFields = map[string]struct{ multipart, json string }{
+	"tls_enabled":            {"TLS", "tls"},
+	"tls_skip_verify":        {"TLSSkipVerify", "tlsskipVerify"},
+	"tls_skip_client_verify": {"TLSSkipClientVerify", "tlsskipClientVerify"},
+}
+
 func resourceEnvironmentCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*APIClient)
 
@@ -81,9 +95,14 @@ func resourceEnvironmentCreate(d *schema.ResourceData, meta interface{}) error {
 	_ = writer.WriteField("URL", d.Get("environment_address").(string))
 	_ = writer.WriteField("EndpointCreationType", strconv.Itoa(d.Get("type").(int)))
 	_ = writer.WriteField("GroupID", strconv.Itoa(d.Get("group_id").(int)))
-	_ = writer.WriteField("TLS", strconv.FormatBool(d.Get("tls_enabled").(bool)))
-	_ = writer.WriteField("TLSSkipVerify", strconv.FormatBool(d.Get("tls_skip_verify").(bool)))
-	_ = writer.WriteField("TLSSkipClientVerify", strconv.FormatBool(d.Get("tls_skip_client_verify").(bool)))
+	// Unset TLS settings are created enabled, as agent environments require.
+	for key, field := range environmentTLSFields {
+		enabled := true
+		if !d.GetRawConfig().GetAttr(key).IsNull() {
+			enabled = d.Get(key).(bool)
+		}
+		_ = writer.WriteField(field.multipart, strconv.FormatBool(enabled))
+	}
 
 	if v, ok := d.GetOk("tag_ids"); ok {
 		tagIds := v.([]interface{})
@@ -158,6 +177,10 @@ func resourceEnvironmentRead(d *schema.ResourceData, meta interface{}) error {
 		PublicURL string `json:"PublicURL"`
 		GroupID   int    `json:"GroupId"`
 		TagIds    []int  `json:"TagIds"`
+		TLSConfig struct {
+			TLS           bool `json:"TLS"`
+			TLSSkipVerify bool `json:"TLSSkipVerify"`
+		} `json:"TLSConfig"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
 		return err
@@ -167,6 +190,8 @@ func resourceEnvironmentRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("type", env.Type)
 	d.Set("group_id", env.GroupID)
 	d.Set("tag_ids", env.TagIds)
+	d.Set("tls_enabled", env.TLSConfig.TLS)
+	d.Set("tls_skip_verify", env.TLSConfig.TLSSkipVerify)
 
 	if env.Type == 1 {
 		d.Set("environment_address", env.URL)
@@ -189,6 +214,12 @@ func resourceEnvironmentUpdate(d *schema.ResourceData, meta interface{}) error {
 		"groupID":   d.Get("group_id").(int),
 		"tagIDs":    d.Get("tag_ids").([]interface{}),
 	}
+	// Unset TLS settings are left as they are in Portainer.
+	for key, field := range environmentTLSFields {
+		if !d.GetRawConfig().GetAttr(key).IsNull() || d.HasChange(key) {
+			payload[field.json] = d.Get(key).(bool)
+		}
+	}
 
 	jsonBody, err := json.Marshal(payload)
 	if err != nil {
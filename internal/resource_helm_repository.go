@@ -0,0 +1,176 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceHelmRepository() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceHelmRepositoryCreate,
+		Read:   resourceHelmRepositoryRead,
+		Delete: resourceHelmRepositoryDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceHelmRepositoryImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"url": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "URL of the Helm repository",
+			},
+			"user_id": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "ID of the user owning the repository; defaults to the authenticated user",
+			},
+		},
+	}
+}
+
+type helmUserRepository struct {
+	ID     int    `json:"Id"`
+	UserID int    `json:"UserId"`
+	URL    string `json:"URL"`
+}
+
+func resourceHelmRepositoryCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+
+	userID := d.Get("user_id").(int)
+	if userID == 0 {
+		var err error
+		if userID, err = currentUserID(client); err != nil {
+			return err
+		}
+	}
+
+	payload := map[string]interface{}{
+		"url": d.Get("url").(string),
+	}
+
+	resp, err := client.DoRequest("POST", fmt.Sprintf("/users/%d/helm/repositories", userID), nil, payload)
+	if err != nil {
+		return fmt.Errorf("failed to create helm repository: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to create helm repository: %s", string(data))
+	}
+
+	var result helmUserRepository
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+
+	d.SetId(strconv.Itoa(result.ID))
+	d.Set("user_id", userID)
+	return resourceHelmRepositoryRead(d, meta)
+}
+
+func resourceHelmRepositoryRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+	userID := d.Get("user_id").(int)
+
+	resp, err := client.DoRequest("GET", fmt.Sprintf("/users/%d/helm/repositories", userID), nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		d.SetId("")
+		return nil
+	} else if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to list helm repositories: %s", string(data))
+	}
+
+	var result struct {
+		UserRepositories []helmUserRepository `json:"UserRepositories"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+
+	for _, repo := range result.UserRepositories {
+		if strconv.Itoa(repo.ID) == d.Id() {
+			// Portainer stores the URL without a trailing slash.
+			if strings.TrimSuffix(d.Get("url").(string), "/") != repo.URL {
+				d.Set("url", repo.URL)
+			}
+			return nil
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceHelmRepositoryDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+	userID := d.Get("user_id").(int)
+
+	resp, err := client.DoRequest("DELETE", fmt.Sprintf("/users/%d/helm/repositories/%s", userID, d.Id()), nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete helm repository: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 && resp.StatusCode != 200 && resp.StatusCode != 404 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete helm repository: %s", string(data))
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceHelmRepositoryImport accepts "<userId>:<repositoryId>".
+func resourceHelmRepositoryImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid import ID %q, expected <userId>:<repositoryId>", d.Id())
+	}
+	userID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID in import ID %q", d.Id())
+	}
+
+	d.SetId(parts[1])
+	d.Set("user_id", userID)
+	return []*schema.ResourceData{d}, nil
+}
+
+// currentUserID returns the ID of the user the provider is authenticated as.
+func currentUserID(client *APIClient) (int, error) {
+	resp, err := client.DoRequest("GET", "/users/me", nil, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read current user: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("failed to read current user: %s", string(data))
+	}
+
+	var user struct {
+		ID int `json:"Id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return 0, err
+	}
+	return user.ID, nil
+}
@@ -1,12 +1,12 @@
 package internal
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -17,6 +17,10 @@ func resourceKubernetesApplication() *schema.Resource {
 		Read:   resourceKubernetesApplicationRead,
 		Update: resourceKubernetesApplicationUpdate,
 		Delete: resourceKubernetesApplicationDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceKubernetesApplicationImport,
+		},
+		CustomizeDiff: resourceKubernetesApplicationCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"endpoint_id": {
@@ -29,8 +33,26 @@ func resourceKubernetesApplication() *schema.Resource {
 				Required: true,
 			},
 			"manifest": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentYAML,
+			},
+			"kind": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Kind of the deployed workload",
+			},
+			"wait_for_rollout": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Wait until the rollout has completed and all replicas are available",
+			},
+			"rollout_timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     600,
+				Description: "Maximum time in seconds to wait for the rollout",
 			},
 		},
 	}
@@ -56,34 +78,18 @@ func resourceKubernetesApplicationCreate(d *schema.ResourceData, meta interface{
 	if !ok || name == "" {
 		return fmt.Errorf("missing metadata.name in manifest")
 	}
-
-	jsonBody, err := json.Marshal(parsed)
-	if err != nil {
-		return fmt.Errorf("failed to encode manifest body: %w", err)
+	kind, _ := parsed["kind"].(string)
+	if _, ok := kubernetesApplicationResources[kind]; !ok {
+		return fmt.Errorf("unsupported application kind %q", kind)
 	}
 
-	url := fmt.Sprintf("%s/endpoints/%d/kubernetes/apis/apps/v1/namespaces/%s/deployments", client.Endpoint, endpointID, namespace)
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
+	if err := createKubernetesObject(client, kubernetesApplicationPath(endpointID, namespace, kind, name), parsed); err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", client.APIKey)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to create Kubernetes Job: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to create Job (%d): %s", resp.StatusCode, string(body))
-	}
 
 	d.SetId(fmt.Sprintf("%d:%s:%s", endpointID, namespace, name))
-	return nil
+	d.Set("kind", kind)
+	return waitForKubernetesApplication(d, client, kind)
 }
 
 func resourceKubernetesApplicationDelete(d *schema.ResourceData, meta interface{}) error {
@@ -91,7 +97,7 @@ func resourceKubernetesApplicationDelete(d *schema.ResourceData, meta interface{
 
 	endpointID, namespace, name := parseApllicationsID(d.Id())
 
-	url := fmt.Sprintf("%s/endpoints/%d/kubernetes/apis/apps/v1/namespaces/%s/deployments/%s", client.Endpoint, endpointID, namespace, name)
+	url := client.Endpoint + kubernetesApplicationPath(endpointID, namespace, kubernetesApplicationKind(d), name)
 
 	req, err := http.NewRequest("DELETE", url, nil)
 	if err != nil {
@@ -115,6 +121,25 @@ func resourceKubernetesApplicationDelete(d *schema.ResourceData, meta interface{
 }
 
 func resourceKubernetesApplicationUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+
+	endpointID, namespace, name := parseApllicationsID(d.Id())
+	kind := kubernetesApplicationKind(d)
+	recreate := d.HasChange("namespace")
+	if parsed, err := parseManifest(d.Get("manifest").(string)); err == nil && parsed["kind"] != kind {
+		recreate = true
+	}
+	var err error
+	if !recreate {
+		recreate, err = updateKubernetesManifest(d, client, kubernetesApplicationPath(endpointID, namespace, kind, name), name)
+	}
+	if err != nil {
+		return err
+	}
+	if !recreate {
+		return waitForKubernetesApplication(d, client, kind)
+	}
+
 	if err := resourceKubernetesApplicationDelete(d, meta); err != nil {
 		return fmt.Errorf("delete during update failed: %w", err)
 	}
@@ -122,7 +147,49 @@ func resourceKubernetesApplicationUpdate(d *schema.ResourceData, meta interface{
 }
 
 func resourceKubernetesApplicationRead(d *schema.ResourceData, meta interface{}) error {
-	return nil
+	client := meta.(*APIClient)
+
+	endpointID, namespace, name := parseApllicationsID(d.Id())
+	kind := kubernetesApplicationKind(d)
+	d.Set("kind", kind)
+	return readKubernetesManifest(d, client, kubernetesApplicationPath(endpointID, namespace, kind, name))
+}
+
+func resourceKubernetesApplicationImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*APIClient)
+
+	endpointID, namespace, name := parseApllicationsID(d.Id())
+	if name == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected <endpointId>:<namespace>:[<kind>/]<name>", d.Id())
+	}
+
+	// The kind may be given as in kubectl, e.g. statefulset/web.
+	kind := "Deployment"
+	if i := strings.Index(name, "/"); i >= 0 {
+		kind = ""
+		for k := range kubernetesApplicationResources {
+			if strings.EqualFold(k, name[:i]) {
+				kind = k
+			}
+		}
+		if kind == "" {
+			return nil, fmt.Errorf("unsupported application kind %q in import ID %q", name[:i], d.Id())
+		}
+		name = name[i+1:]
+		d.SetId(fmt.Sprintf("%d:%s:%s", endpointID, namespace, name))
+	}
+
+	path := kubernetesApplicationPath(endpointID, namespace, kind, name)
+	if err := importKubernetesManifest(d, client, path); err != nil {
+		return nil, err
+	}
+	d.Set("kind", kind)
+	d.Set("endpoint_id", endpointID)
+	d.Set("namespace", namespace)
+	d.Set("wait_for_rollout", false)
+	d.Set("rollout_timeout", 600)
+
+	return []*schema.ResourceData{d}, nil
 }
 
 func parseApllicationsID(id string) (endpointID int, namespace string, name string) {
@@ -135,3 +202,61 @@ func parseApllicationsID(id string) (endpointID int, namespace string, name stri
 	name = parts[2]
 	return
 }
+
+// kubernetesApplicationResources maps the supported workload kinds to their apps/v1 resource names.
+var kubernetesApplicationResources = map[string]string{
+	"Deployment":  "deployments",
+	"StatefulSet": "statefulsets",
+	"DaemonSet":   "daemonsets",
+	"ReplicaSet":  "replicasets",
+}
+
+// kubernetesApplicationKind returns the kind of the deployed workload, which is
+// the kind in state even while a plan changes it. Resources created before other
+// kinds were supported are Deployments.
+func kubernetesApplicationKind(d *schema.ResourceData) string {
+	if kind, _ := d.GetChange("kind"); kind.(string) != "" {
+		return kind.(string)
+	}
+	return "Deployment"
+}
+
+func kubernetesApplicationPath(endpointID int, namespace, kind, name string) string {
+	return fmt.Sprintf("/endpoints/%d/kubernetes/apis/apps/v1/namespaces/%s/%s/%s", endpointID, namespace, kubernetesApplicationResources[kind], name)
+}
+
+func resourceKubernetesApplicationCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("manifest") {
+		return nil
+	}
+	parsed, err := parseManifest(d.Get("manifest").(string))
+	if err != nil {
+		return fmt.Errorf("manifest must be valid JSON or YAML: %w", err)
+	}
+	kind, _ := parsed["kind"].(string)
+	if _, ok := kubernetesApplicationResources[kind]; !ok {
+		return fmt.Errorf("unsupported application kind %q, expected one of Deployment, StatefulSet, DaemonSet or ReplicaSet", kind)
+	}
+
+	// A new kind replaces the workload, so plan the kind it will have.
+	deployed := d.Get("kind").(string)
+	if deployed == "" && d.Id() != "" {
+		deployed = "Deployment"
+	}
+	if kind != deployed {
+		return d.SetNew("kind", kind)
+	}
+	return nil
+}
+
+// waitForKubernetesApplication waits for the Application of the given kind when
+// wait_for_rollout is set.
+func waitForKubernetesApplication(d *schema.ResourceData, client *APIClient, kind string) error {
+	if !d.Get("wait_for_rollout").(bool) {
+		return nil
+	}
+	endpointID, namespace, name := parseApllicationsID(d.Id())
+	timeout := time.Duration(d.Get("rollout_timeout").(int)) * time.Second
+	path := kubernetesApplicationPath(endpointID, namespace, kind, name)
+	return waitForKubernetesRollout(client, endpointID, namespace, path, timeout)
+}
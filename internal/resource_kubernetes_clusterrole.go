@@ -1,8 +1,6 @@
 package internal
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,6 +15,9 @@ func resourceKubernetesClusterRoles() *schema.Resource {
 		Read:   resourceKubernetesClusterRolesRead,
 		Update: resourceKubernetesClusterRolesUpdate,
 		Delete: resourceKubernetesClusterRolesDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceKubernetesClusterRolesImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"endpoint_id": {
@@ -25,8 +26,9 @@ func resourceKubernetesClusterRoles() *schema.Resource {
 				ForceNew: true,
 			},
 			"manifest": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentYAML,
 			},
 		},
 	}
@@ -52,32 +54,11 @@ func resourceKubernetesClusterRolesCreate(d *schema.ResourceData, meta interface
 		return fmt.Errorf("missing metadata.name in manifest")
 	}
 
-	jsonBody, err := json.Marshal(parsed)
-	if err != nil {
-		return fmt.Errorf("failed to encode manifest body: %w", err)
-	}
-
-	url := fmt.Sprintf("%s/endpoints/%d/kubernetes/apis/rbac.authorization.k8s.io/v1/clusterroles", client.Endpoint, endpointID)
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
+	if err := createKubernetesObject(client, kubernetesClusterRolesPath(endpointID, name), parsed); err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", client.APIKey)
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to create Kubernetes Job: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to create Job (%d): %s", resp.StatusCode, string(body))
-	}
-
-	d.SetId(fmt.Sprintf("%d:%s:%s", endpointID, name))
+	d.SetId(fmt.Sprintf("%d:%s", endpointID, name))
 	return nil
 }
 
@@ -110,6 +91,14 @@ func resourceKubernetesClusterRolesDelete(d *schema.ResourceData, meta interface
 }
 
 func resourceKubernetesClusterRolesUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+
+	endpointID, name := parseClusterRolesID(d.Id())
+	recreate, err := updateKubernetesManifest(d, client, kubernetesClusterRolesPath(endpointID, name), name)
+	if err != nil || !recreate {
+		return err
+	}
+
 	if err := resourceKubernetesClusterRolesDelete(d, meta); err != nil {
 		return fmt.Errorf("delete during update failed: %w", err)
 	}
@@ -117,15 +106,39 @@ func resourceKubernetesClusterRolesUpdate(d *schema.ResourceData, meta interface
 }
 
 func resourceKubernetesClusterRolesRead(d *schema.ResourceData, meta interface{}) error {
-	return nil
+	client := meta.(*APIClient)
+
+	endpointID, name := parseClusterRolesID(d.Id())
+	return readKubernetesManifest(d, client, kubernetesClusterRolesPath(endpointID, name))
+}
+
+func resourceKubernetesClusterRolesImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*APIClient)
+
+	endpointID, name := parseClusterRolesID(d.Id())
+	if name == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected <endpointId>:<name>", d.Id())
+	}
+
+	path := kubernetesClusterRolesPath(endpointID, name)
+	if err := importKubernetesManifest(d, client, path); err != nil {
+		return nil, err
+	}
+	d.Set("endpoint_id", endpointID)
+
+	return []*schema.ResourceData{d}, nil
 }
 
 func parseClusterRolesID(id string) (endpointID int, name string) {
 	parts := strings.SplitN(id, ":", 3)
-	if len(parts) != 3 {
+	if len(parts) < 2 {
 		return 0, ""
 	}
 	fmt.Sscanf(parts[0], "%d", &endpointID)
 	name = parts[1]
 	return
 }
+
+func kubernetesClusterRolesPath(endpointID int, name string) string {
+	return fmt.Sprintf("/endpoints/%d/kubernetes/apis/rbac.authorization.k8s.io/v1/clusterroles/%s", endpointID, name)
+}
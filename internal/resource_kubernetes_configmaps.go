@@ -1,8 +1,6 @@
 package internal
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,6 +15,9 @@ func resourceKubernetesConfigMaps() *schema.Resource {
 		Read:   resourceKubernetesConfigMapsRead,
 		Update: resourceKubernetesConfigMapsUpdate,
 		Delete: resourceKubernetesConfigMapsDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceKubernetesConfigMapsImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"endpoint_id": {
@@ -29,8 +30,9 @@ func resourceKubernetesConfigMaps() *schema.Resource {
 				Required: true,
 			},
 			"manifest": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentYAML,
 			},
 		},
 	}
@@ -57,30 +59,9 @@ func resourceKubernetesConfigMapsCreate(d *schema.ResourceData, meta interface{}
 		return fmt.Errorf("missing metadata.name in manifest")
 	}
 
-	jsonBody, err := json.Marshal(parsed)
-	if err != nil {
-		return fmt.Errorf("failed to encode manifest body: %w", err)
-	}
-
-	url := fmt.Sprintf("%s/endpoints/%d/kubernetes/api/v1/namespaces/%s/configmaps", client.Endpoint, endpointID, namespace)
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
+	if err := createKubernetesObject(client, kubernetesConfigMapsPath(endpointID, namespace, name), parsed); err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", client.APIKey)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to create Kubernetes Job: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to create Job (%d): %s", resp.StatusCode, string(body))
-	}
 
 	d.SetId(fmt.Sprintf("%d:%s:%s", endpointID, namespace, name))
 	return nil
@@ -115,6 +96,18 @@ func resourceKubernetesConfigMapsDelete(d *schema.ResourceData, meta interface{}
 }
 
 func resourceKubernetesConfigMapsUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+
+	endpointID, namespace, name := parseConfigMapsID(d.Id())
+	recreate := d.HasChange("namespace")
+	var err error
+	if !recreate {
+		recreate, err = updateKubernetesManifest(d, client, kubernetesConfigMapsPath(endpointID, namespace, name), name)
+	}
+	if err != nil || !recreate {
+		return err
+	}
+
 	if err := resourceKubernetesConfigMapsDelete(d, meta); err != nil {
 		return fmt.Errorf("delete during update failed: %w", err)
 	}
@@ -122,7 +115,28 @@ func resourceKubernetesConfigMapsUpdate(d *schema.ResourceData, meta interface{}
 }
 
 func resourceKubernetesConfigMapsRead(d *schema.ResourceData, meta interface{}) error {
-	return nil
+	client := meta.(*APIClient)
+
+	endpointID, namespace, name := parseConfigMapsID(d.Id())
+	return readKubernetesManifest(d, client, kubernetesConfigMapsPath(endpointID, namespace, name))
+}
+
+func resourceKubernetesConfigMapsImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*APIClient)
+
+	endpointID, namespace, name := parseConfigMapsID(d.Id())
+	if name == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected <endpointId>:<namespace>:<name>", d.Id())
+	}
+
+	path := kubernetesConfigMapsPath(endpointID, namespace, name)
+	if err := importKubernetesManifest(d, client, path); err != nil {
+		return nil, err
+	}
+	d.Set("endpoint_id", endpointID)
+	d.Set("namespace", namespace)
+
+	return []*schema.ResourceData{d}, nil
 }
 
 func parseConfigMapsID(id string) (endpointID int, namespace string, name string) {
@@ -135,3 +149,7 @@ func parseConfigMapsID(id string) (endpointID int, namespace string, name string
 	name = parts[2]
 	return
 }
+
+func kubernetesConfigMapsPath(endpointID int, namespace, name string) string {
+	return fmt.Sprintf("/endpoints/%d/kubernetes/api/v1/namespaces/%s/configmaps/%s", endpointID, namespace, name)
+}
@@ -1,21 +1,30 @@
 package internal
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"gopkg.in/yaml.v3"
 )
 
 func resourceKubernetesHelm() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceKubernetesHelmCreate,
 		Read:   resourceKubernetesHelmRead,
+		Update: resourceKubernetesHelmUpdate,
 		Delete: resourceKubernetesHelmDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceKubernetesHelmImport,
+		},
+		CustomizeDiff: resourceKubernetesHelmCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"environment_id": {
@@ -26,7 +35,12 @@ func resourceKubernetesHelm() *schema.Resource {
 			"chart": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Chart version to install; the latest version when unset",
 			},
 			"name": {
 				Type:     schema.TypeString,
@@ -41,13 +55,94 @@ func resourceKubernetesHelm() *schema.Resource {
 			"repo": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
+				// The repository is not reported by Portainer, so it is unknown after import.
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return old == "" && d.Id() != ""
+				},
 			},
 			"values": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "",
-				ForceNew: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				DiffSuppressFunc: suppressEquivalentHelmValues,
+			},
+			"values_files": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "YAML values documents merged in order before values",
+			},
+			"set": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Values set with helm --set semantics, applied after values_files and values",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Path of the value, e.g. ingress.hosts[0].name",
+						},
+						"value": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "auto",
+							ValidateFunc: validation.StringInSlice([]string{"auto", "string"}, false),
+							Description:  "auto parses booleans, null, integers and {a,b} lists like helm --set; string keeps the value as is",
+						},
+					},
+				},
+			},
+			"set_sensitive": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Like set, for values that must not be shown in plans",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Path of the value, e.g. ingress.hosts[0].name",
+						},
+						"value": {
+							Type:      schema.TypeString,
+							Required:  true,
+							Sensitive: true,
+						},
+						"type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "auto",
+							ValidateFunc: validation.StringInSlice([]string{"auto", "string"}, false),
+							Description:  "auto parses booleans, null, integers and {a,b} lists like helm --set; string keeps the value as is",
+						},
+					},
+				},
+			},
+			"merged_values": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Values of the release after merging values_files, values, set and set_sensitive",
+			},
+			"revision": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Current revision of the release",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the release, e.g. deployed or failed",
+			},
+			"app_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Application version of the deployed chart",
 			},
 		},
 	}
@@ -57,43 +152,247 @@ func resourceKubernetesHelmCreate(d *schema.ResourceData, meta interface{}) erro
 	client := meta.(*APIClient)
 	id := d.Get("environment_id").(int)
 
+	if err := installHelmRelease(d, client, id); err != nil {
+		return fmt.Errorf("failed to install helm chart: %w", err)
+	}
+
+	d.SetId(fmt.Sprintf("%d:%s:%s", id, d.Get("namespace").(string), d.Get("name").(string)))
+	return resourceKubernetesHelmRead(d, meta)
+}
+
+func resourceKubernetesHelmUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+	envID, _, _, err := parseHelmID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// Installing an existing release upgrades it in place.
+	if err := installHelmRelease(d, client, envID); err != nil {
+		return fmt.Errorf("failed to upgrade helm release: %w", err)
+	}
+	return resourceKubernetesHelmRead(d, meta)
+}
+
+// installHelmRelease installs the configured chart, or upgrades the release if it exists.
+func installHelmRelease(d *schema.ResourceData, client *APIClient, envID int) error {
+	values, err := composeHelmValues(d.Get)
+	if err != nil {
+		return err
+	}
+
+	// The diff of repo is suppressed after import, leaving it empty in the plan;
+	// the configured repository is the one to install from.
+	repo := d.Get("repo").(string)
+	if v := d.GetRawConfig().GetAttr("repo"); repo == "" && v.IsKnown() && !v.IsNull() {
+		repo = v.AsString()
+		d.Set("repo", repo)
+	}
+
 	body := map[string]interface{}{
 		"chart":     d.Get("chart").(string),
 		"name":      d.Get("name").(string),
 		"namespace": d.Get("namespace").(string),
-		"repo":      d.Get("repo").(string),
-		"values":    d.Get("values").(string),
+		"repo":      repo,
+		"values":    values,
+	}
+	// Without a configured version this is the deployed one, so upgrades keep the chart version.
+	if v, ok := d.GetOk("version"); ok {
+		body["version"] = v.(string)
 	}
 
-	jsonBody, _ := json.Marshal(body)
-	url := fmt.Sprintf("%s/endpoints/%d/kubernetes/helm", client.Endpoint, id)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	resp, err := client.DoRequest(http.MethodPost, fmt.Sprintf("/endpoints/%d/kubernetes/helm", envID), nil, body)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("X-API-Key", client.APIKey)
-	req.Header.Set("Content-Type", "application/json")
+	defer resp.Body.Close()
 
-	resp, err := http.DefaultClient.Do(req)
+	if resp.StatusCode >= 400 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s", string(data))
+	}
+
+	d.Set("merged_values", values)
+	return nil
+}
+
+type helmReleaseElement struct {
+	Name       string      `json:"name"`
+	Namespace  string      `json:"namespace"`
+	Chart      string      `json:"chart"`
+	AppVersion string      `json:"app_version"`
+	Status     string      `json:"status"`
+	Revision   interface{} `json:"revision"`
+}
+
+func resourceKubernetesHelmRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+	envID, namespace, release, err := parseHelmID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/endpoints/%d/kubernetes/helm?namespace=%s&filter=%s", envID, url.QueryEscape(namespace), url.QueryEscape(release))
+	resp, err := client.DoRequest("GET", path, nil, nil)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
+	if resp.StatusCode != 200 {
 		data, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to install helm chart: %s", string(data))
+		return fmt.Errorf("failed to list helm releases: %s", string(data))
 	}
 
-	d.SetId(fmt.Sprintf("%d:%s:%s", id, d.Get("namespace").(string), d.Get("name").(string)))
-	return resourceKubernetesHelmRead(d, meta)
+	var releases []helmReleaseElement
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return err
+	}
+
+	for _, r := range releases {
+		if r.Name != release || r.Namespace != namespace {
+			continue
+		}
+		d.Set("environment_id", envID)
+		d.Set("namespace", r.Namespace)
+		d.Set("name", r.Name)
+		// Keep the configured chart reference unless the deployed chart is a different one.
+		chart := helmChartName(r.Chart)
+		if !strings.HasSuffix(d.Get("chart").(string), chart) {
+			d.Set("chart", chart)
+		}
+		if r.Chart != chart {
+			d.Set("version", strings.TrimPrefix(r.Chart, chart+"-"))
+		}
+		d.Set("app_version", r.AppVersion)
+		d.Set("status", r.Status)
+		revision, _ := strconv.Atoi(fmt.Sprint(r.Revision))
+		d.Set("revision", revision)
+
+		values, err := getHelmReleaseValues(client, envID, namespace, release)
+		if err != nil {
+			return err
+		}
+		if values == nil {
+			// Without values from Portainer, assume the configured ones are deployed.
+			if d.Get("merged_values").(string) == "" {
+				if composed, err := composeHelmValues(d.Get); err == nil {
+					d.Set("merged_values", composed)
+				}
+			}
+			return nil
+		}
+		d.Set("merged_values", *values)
+		if !usesHelmValuesComposition(d.Get) && !helmValuesEquivalent(d.Get("values").(string), *values) {
+			d.Set("values", *values)
+		}
+		return nil
+	}
+
+	d.SetId("")
+	return nil
 }
 
-func resourceKubernetesHelmRead(d *schema.ResourceData, meta interface{}) error {
-	// No-op for now
+// getHelmReleaseValues returns the user-supplied values of a release, or nil
+// when the Portainer version does not expose them.
+func getHelmReleaseValues(client *APIClient, envID int, namespace, release string) (*string, error) {
+	path := fmt.Sprintf("/endpoints/%d/kubernetes/helm/%s?namespace=%s", envID, url.PathEscape(release), url.QueryEscape(namespace))
+	resp, err := client.DoRequest("GET", path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 || resp.StatusCode == 405 {
+		return nil, nil
+	} else if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to read helm release: %s", string(data))
+	}
+
+	var result struct {
+		Values *struct {
+			UserSuppliedValues string `json:"userSuppliedValues"`
+		} `json:"values"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode helm release: %w", err)
+	}
+	if result.Values == nil {
+		return nil, nil
+	}
+	return &result.Values.UserSuppliedValues, nil
+}
+
+// resourceKubernetesHelmCustomizeDiff plans an upgrade when the merged values
+// differ from the deployed ones, whichever of values_files, values, set and
+// set_sensitive changed.
+func resourceKubernetesHelmCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	for _, key := range []string{"values", "values_files", "set", "set_sensitive"} {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("merged_values")
+		}
+	}
+
+	values, err := composeHelmValues(d.Get)
+	if err != nil {
+		return err
+	}
+	if d.Id() == "" || !helmValuesEquivalent(d.Get("merged_values").(string), values) {
+		return d.SetNewComputed("merged_values")
+	}
 	return nil
 }
 
+// suppressEquivalentHelmValues ignores formatting-only differences between values.
+func suppressEquivalentHelmValues(k, old, new string, d *schema.ResourceData) bool {
+	return helmValuesEquivalent(old, new)
+}
+
+// helmValuesEquivalent compares two YAML values documents. Empty documents, null
+// and {} all mean "no values".
+func helmValuesEquivalent(a, b string) bool {
+	if yamlEquivalent(a, b) {
+		return true
+	}
+	var va, vb map[string]interface{}
+	if err := yaml.Unmarshal([]byte(a), &va); err != nil {
+		return false
+	}
+	if err := yaml.Unmarshal([]byte(b), &vb); err != nil {
+		return false
+	}
+	return len(va) == 0 && len(vb) == 0
+}
+
+// resourceKubernetesHelmImport accepts "<environmentId>:<namespace>:<release>".
+func resourceKubernetesHelmImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, _, err := parseHelmID(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func parseHelmID(id string) (int, string, string, error) {
+	parts := strings.SplitN(id, ":", 3)
+	if len(parts) != 3 {
+		return 0, "", "", fmt.Errorf("invalid ID format, expected 'envID:namespace:release': %s", id)
+	}
+	envID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", "", fmt.Errorf("invalid environment ID in %q: %w", id, err)
+	}
+	return envID, parts[1], parts[2], nil
+}
+
+// helmChartName strips the version suffix from a chart reference such as "nginx-15.1.0".
+func helmChartName(chart string) string {
+	if i := strings.LastIndex(chart, "-"); i > 0 && i+1 < len(chart) && chart[i+1] >= '0' && chart[i+1] <= '9' {
+		return chart[:i]
+	}
+	return chart
+}
+
 func resourceKubernetesHelmDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*APIClient)
 	idParts := strings.SplitN(d.Id(), ":", 3)
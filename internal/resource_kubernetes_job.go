@@ -1,12 +1,11 @@
 package internal
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -17,6 +16,9 @@ func resourceKubernetesJob() *schema.Resource {
 		Read:   resourceKubernetesJobRead,
 		Update: resourceKubernetesJobUpdate,
 		Delete: resourceKubernetesJobDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceKubernetesJobImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"endpoint_id": {
@@ -29,8 +31,21 @@ func resourceKubernetesJob() *schema.Resource {
 				Required: true,
 			},
 			"manifest": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentYAML,
+			},
+			"wait_for_completion": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Wait until the Job has completed successfully",
+			},
+			"completion_timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     600,
+				Description: "Maximum time in seconds to wait for the Job to complete",
 			},
 		},
 	}
@@ -57,33 +72,12 @@ func resourceKubernetesJobCreate(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("missing metadata.name in manifest")
 	}
 
-	jsonBody, err := json.Marshal(parsed)
-	if err != nil {
-		return fmt.Errorf("failed to encode manifest body: %w", err)
-	}
-
-	url := fmt.Sprintf("%s/endpoints/%d/kubernetes/apis/batch/v1/namespaces/%s/jobs", client.Endpoint, endpointID, namespace)
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
+	if err := createKubernetesObject(client, kubernetesJobPath(endpointID, namespace, name), parsed); err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", client.APIKey)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to create Kubernetes Job: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to create Job (%d): %s", resp.StatusCode, string(body))
-	}
 
 	d.SetId(fmt.Sprintf("%d:%s:%s", endpointID, namespace, name))
-	return nil
+	return waitForKubernetesJob(d, client)
 }
 
 func resourceKubernetesJobDelete(d *schema.ResourceData, meta interface{}) error {
@@ -115,6 +109,21 @@ func resourceKubernetesJobDelete(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceKubernetesJobUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+
+	endpointID, namespace, name := parseJobID(d.Id())
+	recreate := d.HasChange("namespace")
+	var err error
+	if !recreate {
+		recreate, err = updateKubernetesManifest(d, client, kubernetesJobPath(endpointID, namespace, name), name)
+	}
+	if err != nil {
+		return err
+	}
+	if !recreate {
+		return waitForKubernetesJob(d, client)
+	}
+
 	if err := resourceKubernetesJobDelete(d, meta); err != nil {
 		return fmt.Errorf("delete during update failed: %w", err)
 	}
@@ -122,7 +131,30 @@ func resourceKubernetesJobUpdate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceKubernetesJobRead(d *schema.ResourceData, meta interface{}) error {
-	return nil
+	client := meta.(*APIClient)
+
+	endpointID, namespace, name := parseJobID(d.Id())
+	return readKubernetesManifest(d, client, kubernetesJobPath(endpointID, namespace, name))
+}
+
+func resourceKubernetesJobImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*APIClient)
+
+	endpointID, namespace, name := parseJobID(d.Id())
+	if name == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected <endpointId>:<namespace>:<name>", d.Id())
+	}
+
+	path := kubernetesJobPath(endpointID, namespace, name)
+	if err := importKubernetesManifest(d, client, path); err != nil {
+		return nil, err
+	}
+	d.Set("endpoint_id", endpointID)
+	d.Set("namespace", namespace)
+	d.Set("wait_for_completion", false)
+	d.Set("completion_timeout", 600)
+
+	return []*schema.ResourceData{d}, nil
 }
 
 func parseJobID(id string) (endpointID int, namespace string, name string) {
@@ -135,3 +167,17 @@ func parseJobID(id string) (endpointID int, namespace string, name string) {
 	name = parts[2]
 	return
 }
+
+func kubernetesJobPath(endpointID int, namespace, name string) string {
+	return fmt.Sprintf("/endpoints/%d/kubernetes/apis/batch/v1/namespaces/%s/jobs/%s", endpointID, namespace, name)
+}
+
+// waitForKubernetesJob waits for the Job when wait_for_completion is set.
+func waitForKubernetesJob(d *schema.ResourceData, client *APIClient) error {
+	if !d.Get("wait_for_completion").(bool) {
+		return nil
+	}
+	endpointID, namespace, name := parseJobID(d.Id())
+	timeout := time.Duration(d.Get("completion_timeout").(int)) * time.Second
+	return waitForKubernetesRollout(client, endpointID, namespace, kubernetesJobPath(endpointID, namespace, name), timeout)
+}
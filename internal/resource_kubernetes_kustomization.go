@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// resourceKubernetesKustomization renders a kustomization at plan time and
+// manages the rendered objects like portainer_kubernetes_manifest does.
+func resourceKubernetesKustomization() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceKubernetesManifestCreate,
+		Read:          resourceKubernetesManifestRead,
+		Update:        resourceKubernetesManifestUpdate,
+		Delete:        resourceKubernetesManifestDelete,
+		CustomizeDiff: resourceKubernetesKustomizationCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"endpoint_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Path of the local directory holding kustomization.yaml",
+			},
+			"namespace": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+				Description: "Namespace used for namespaced objects the kustomization does not place in a namespace",
+			},
+			"manifest": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Objects rendered by kustomize, as multi-document YAML",
+			},
+			"objects": kubernetesManifestObjectsSchema(),
+		},
+	}
+}
+
+// resourceKubernetesKustomizationCustomizeDiff renders the kustomization, so
+// changes to the overlay, and drift of the live objects, show up in the plan.
+func resourceKubernetesKustomizationCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("path") {
+		return d.SetNewComputed("manifest")
+	}
+
+	rendered, err := renderKustomization(d.Get("path").(string))
+	if err != nil {
+		return err
+	}
+	if !manifestDocumentsEquivalent(d.Get("manifest").(string), rendered) {
+		return d.SetNew("manifest", rendered)
+	}
+	return nil
+}
+
+// renderKustomization builds the kustomization in dir, like `kustomize build`.
+func renderKustomization(dir string) (string, error) {
+	// As with the kustomize CLI, sortOptions from kustomization.yaml apply, and
+	// otherwise the legacy order creates namespaces and CRDs first.
+	opts := krusty.MakeDefaultOptions()
+	opts.Reorder = krusty.ReorderOptionUnspecified
+	k := krusty.MakeKustomizer(opts)
+	resources, err := k.Run(filesys.MakeFsOnDisk(), dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to build kustomization %s: %w", dir, err)
+	}
+
+	out, err := resources.AsYaml()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode kustomization %s: %w", dir, err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,169 @@
+package internal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// fakeServerSideApply is a minimal Kubernetes API behind the Portainer proxy
+// that tracks the fields set by every field manager and operation, like the API
+// server does. An apply replaces the fields owned by its manager, so fields left
+// out of the applied object are pruned unless another manager set them.
+type fakeServerSideApply struct {
+	mu      sync.Mutex
+	objects map[string]map[string]map[string]interface{} // path -> manager -> object
+}
+
+func (f *fakeServerSideApply) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/endpoints/1/kubernetes/api/v1" {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"resources": []map[string]interface{}{{"name": "configmaps", "kind": "ConfigMap", "namespaced": true}},
+		})
+		return
+	}
+
+	var body map[string]interface{}
+	if r.Body != nil {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+	manager := r.URL.Query().Get("fieldManager")
+
+	switch r.Method {
+	case http.MethodGet:
+		managed, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(f.live(managed))
+	case http.MethodPost:
+		name := body["metadata"].(map[string]interface{})["name"].(string)
+		path := r.URL.Path + "/" + name
+		if _, ok := f.objects[path]; ok {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		f.objects[path] = map[string]map[string]interface{}{manager + "/Update": body}
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodPatch:
+		if r.Header.Get("Content-Type") != "application/apply-patch+yaml" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		if f.objects[r.URL.Path] == nil {
+			f.objects[r.URL.Path] = map[string]map[string]interface{}{}
+		}
+		f.objects[r.URL.Path][manager+"/Apply"] = body
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+	}
+}
+
+// live merges the fields of all managers into the object the API server returns.
+func (f *fakeServerSideApply) live(managed map[string]map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	for _, obj := range managed {
+		mergeFakeObject(out, obj)
+	}
+	return out
+}
+
+func mergeFakeObject(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if sm, ok := v.(map[string]interface{}); ok {
+			dm, ok := dst[k].(map[string]interface{})
+			if !ok {
+				dm = map[string]interface{}{}
+				dst[k] = dm
+			}
+			mergeFakeObject(dm, sm)
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+func writeKustomizationFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestKubernetesKustomizationPrunesRemovedFields(t *testing.T) {
+	fake := &fakeServerSideApply{objects: map[string]map[string]map[string]interface{}{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client := &APIClient{Endpoint: server.URL}
+
+	dir := t.TempDir()
+	writeKustomizationFiles(t, dir, map[string]string{
+		"configmap.yaml":     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\ndata:\n  a: \"1\"\n",
+		"patch.yaml":         "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\ndata:\n  b: \"2\"\n",
+		"kustomization.yaml": "resources:\n- configmap.yaml\npatches:\n- path: patch.yaml\n",
+	})
+
+	r := resourceKubernetesKustomization()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"endpoint_id": 1,
+		"path":        dir,
+	})
+
+	rendered, err := renderKustomization(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Set("manifest", rendered)
+	if err := resourceKubernetesManifestCreate(d, client); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	path := "/endpoints/1/kubernetes/api/v1/namespaces/default/configmaps/app"
+	live := fake.live(fake.objects[path])
+	if data := live["data"].(map[string]interface{}); data["a"] != "1" || data["b"] != "2" {
+		t.Fatalf("unexpected data after create: %v", data)
+	}
+
+	// Drop the patch that adds key b.
+	writeKustomizationFiles(t, dir, map[string]string{
+		"kustomization.yaml": "resources:\n- configmap.yaml\n",
+	})
+	rendered, err = renderKustomization(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Set("manifest", rendered)
+	if err := resourceKubernetesManifestUpdate(d, client); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	live = fake.live(fake.objects[path])
+	data := live["data"].(map[string]interface{})
+	if _, ok := data["b"]; ok {
+		t.Errorf("key b removed from the kustomization is still live: %v", data)
+	}
+	if data["a"] != "1" {
+		t.Errorf("key a was lost: %v", data)
+	}
+	for manager := range fake.objects[path] {
+		if manager != kubernetesFieldManager+"/Apply" {
+			t.Errorf("fields owned by unexpected manager %q", manager)
+		}
+	}
+	if strings.Contains(rendered, "b:") {
+		t.Fatalf("kustomization still renders key b: %s", rendered)
+	}
+}
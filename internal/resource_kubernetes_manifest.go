@@ -0,0 +1,526 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceKubernetesManifest() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKubernetesManifestCreate,
+		Read:   resourceKubernetesManifestRead,
+		Update: resourceKubernetesManifestUpdate,
+		Delete: resourceKubernetesManifestDelete,
+
+		Schema: map[string]*schema.Schema{
+			"endpoint_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"namespace": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+				Description: "Namespace used for namespaced objects that do not set metadata.namespace",
+			},
+			"manifest": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentManifestDocuments,
+				Description:      "One or more Kubernetes objects as JSON or multi-document YAML",
+			},
+			"objects": kubernetesManifestObjectsSchema(),
+		},
+	}
+}
+
+// kubernetesManifestObjectsSchema describes the objects tracked by a manifest
+// resource, in manifest order.
+func kubernetesManifestObjectsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Objects managed by this resource, in manifest order",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"api_version": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"kind": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"namespace": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"path": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+}
+
+// kubernetesManifestObject is one object of a portainer_kubernetes_manifest.
+type kubernetesManifestObject struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	Path       string
+}
+
+func (o kubernetesManifestObject) key() string {
+	return strings.Join([]string{o.APIVersion, o.Kind, o.Namespace, o.Name}, "/")
+}
+
+func (o kubernetesManifestObject) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"api_version": o.APIVersion,
+		"kind":        o.Kind,
+		"namespace":   o.Namespace,
+		"name":        o.Name,
+		"path":        o.Path,
+	}
+}
+
+// kubernetesAPIResource is an entry of a Kubernetes API discovery document.
+type kubernetesAPIResource struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Namespaced bool   `json:"namespaced"`
+}
+
+// kubernetesDiscovery resolves apiVersion/kind pairs to REST paths through the
+// Portainer Kubernetes proxy, caching one discovery document per group version.
+type kubernetesDiscovery struct {
+	client     *APIClient
+	endpointID int
+	resources  map[string][]kubernetesAPIResource
+}
+
+func newKubernetesDiscovery(client *APIClient, endpointID int) *kubernetesDiscovery {
+	return &kubernetesDiscovery{
+		client:     client,
+		endpointID: endpointID,
+		resources:  map[string][]kubernetesAPIResource{},
+	}
+}
+
+// groupVersionPath returns the API root of apiVersion, e.g. /api/v1 or /apis/apps/v1.
+func (k *kubernetesDiscovery) groupVersionPath(apiVersion string) string {
+	if strings.Contains(apiVersion, "/") {
+		return fmt.Sprintf("/endpoints/%d/kubernetes/apis/%s", k.endpointID, apiVersion)
+	}
+	return fmt.Sprintf("/endpoints/%d/kubernetes/api/%s", k.endpointID, apiVersion)
+}
+
+func (k *kubernetesDiscovery) fetch(apiVersion string) ([]kubernetesAPIResource, error) {
+	resp, err := k.client.DoRequest(http.MethodGet, k.groupVersionPath(apiVersion), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover API %s: %w", apiVersion, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, nil
+	} else if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to discover API %s (%d): %s", apiVersion, resp.StatusCode, string(body))
+	}
+
+	var list struct {
+		Resources []kubernetesAPIResource `json:"resources"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode API %s: %w", apiVersion, err)
+	}
+	return list.Resources, nil
+}
+
+// lookup finds the resource serving kind in apiVersion. Kinds defined by a CRD
+// created earlier in the same manifest may take a moment to be served, so a
+// miss is retried for up to wait.
+func (k *kubernetesDiscovery) lookup(apiVersion, kind string, wait time.Duration) (*kubernetesAPIResource, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		resources, ok := k.resources[apiVersion]
+		if !ok {
+			var err error
+			resources, err = k.fetch(apiVersion)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, r := range resources {
+			// Skip subresources such as deployments/status.
+			if r.Kind == kind && !strings.Contains(r.Name, "/") {
+				k.resources[apiVersion] = resources
+				return &r, nil
+			}
+		}
+
+		delete(k.resources, apiVersion)
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("kind %s is not served by API %s", kind, apiVersion)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// resolve determines the object described by doc and its REST path, defaulting
+// the namespace of namespaced objects to defaultNamespace.
+func (k *kubernetesDiscovery) resolve(doc map[string]interface{}, defaultNamespace string, wait time.Duration) (kubernetesManifestObject, error) {
+	apiVersion, _ := doc["apiVersion"].(string)
+	kind, _ := doc["kind"].(string)
+	metadata, _ := doc["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	if apiVersion == "" || kind == "" || name == "" {
+		return kubernetesManifestObject{}, fmt.Errorf("every object needs apiVersion, kind and metadata.name")
+	}
+
+	resource, err := k.lookup(apiVersion, kind, wait)
+	if err != nil {
+		return kubernetesManifestObject{}, err
+	}
+
+	obj := kubernetesManifestObject{APIVersion: apiVersion, Kind: kind, Name: name}
+	if resource.Namespaced {
+		obj.Namespace, _ = metadata["namespace"].(string)
+		if obj.Namespace == "" {
+			obj.Namespace = defaultNamespace
+			metadata["namespace"] = obj.Namespace
+		}
+		obj.Path = fmt.Sprintf("%s/namespaces/%s/%s/%s", k.groupVersionPath(apiVersion), obj.Namespace, resource.Name, name)
+	} else {
+		obj.Path = fmt.Sprintf("%s/%s/%s", k.groupVersionPath(apiVersion), resource.Name, name)
+	}
+	return obj, nil
+}
+
+func resourceKubernetesManifestCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+	endpointID := d.Get("endpoint_id").(int)
+
+	docs, _, err := parseManifestDocuments(d.Get("manifest").(string))
+	if err != nil {
+		return fmt.Errorf("manifest must be valid JSON or YAML: %w", err)
+	}
+	if len(docs) == 0 {
+		return fmt.Errorf("manifest does not contain any object")
+	}
+
+	discovery := newKubernetesDiscovery(client, endpointID)
+	objects := make([]kubernetesManifestObject, 0, len(docs))
+	for _, doc := range docs {
+		obj, err := discovery.resolve(doc, d.Get("namespace").(string), time.Minute)
+		if err == nil {
+			err = createKubernetesManifestObject(client, obj, doc)
+		}
+		if err != nil {
+			// Keep track of the objects created so far.
+			if len(objects) > 0 {
+				d.SetId(fmt.Sprintf("%d:%s", endpointID, objects[0].key()))
+				setKubernetesManifestObjects(d, objects)
+			}
+			return err
+		}
+		objects = append(objects, obj)
+	}
+
+	d.SetId(fmt.Sprintf("%d:%s", endpointID, objects[0].key()))
+	setKubernetesManifestObjects(d, objects)
+	return nil
+}
+
+func resourceKubernetesManifestRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+
+	manifest := d.Get("manifest").(string)
+	docs, nodes, err := parseManifestDocuments(manifest)
+	if err != nil {
+		// Leave an unparsable manifest alone; Create/Update report the error.
+		return nil
+	}
+	desired := make(map[string]int, len(docs))
+	for i, doc := range docs {
+		for _, key := range kubernetesManifestDocKeys(doc, d.Get("namespace").(string)) {
+			desired[key] = i
+		}
+	}
+
+	drifted := false
+	rendered := make([]string, len(docs))
+	present := make([]bool, len(docs))
+	var objects []kubernetesManifestObject
+	for _, obj := range getKubernetesManifestObjects(d) {
+		live, err := getKubernetesObject(client, obj.Path)
+		if err != nil {
+			return err
+		}
+		if live == nil {
+			drifted = true
+			continue
+		}
+		objects = append(objects, obj)
+
+		i, ok := desired[obj.key()]
+		if !ok {
+			continue
+		}
+		present[i] = true
+		out, changed, err := renderKubernetesDrift(nodes[i], docs[i], live)
+		if err != nil {
+			return err
+		}
+		if changed {
+			rendered[i] = out
+			drifted = true
+		}
+	}
+
+	if len(objects) == 0 {
+		d.SetId("")
+		return nil
+	}
+	setKubernetesManifestObjects(d, objects)
+
+	if !drifted {
+		return nil
+	}
+
+	// Objects deleted outside of Terraform are left out of the manifest, so the
+	// plan shows them being added back.
+	var parts []string
+	for i, node := range nodes {
+		if !present[i] {
+			continue
+		}
+		if rendered[i] == "" {
+			out, err := encodeKubernetesYAML(node)
+			if err != nil {
+				return fmt.Errorf("failed to encode manifest: %w", err)
+			}
+			rendered[i] = out
+		}
+		parts = append(parts, rendered[i])
+	}
+	d.Set("manifest", strings.Join(parts, "---\n"))
+	return nil
+}
+
+func resourceKubernetesManifestUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+	endpointID := d.Get("endpoint_id").(int)
+
+	docs, _, err := parseManifestDocuments(d.Get("manifest").(string))
+	if err != nil {
+		return fmt.Errorf("manifest must be valid JSON or YAML: %w", err)
+	}
+	if len(docs) == 0 {
+		return fmt.Errorf("manifest does not contain any object")
+	}
+
+	previous := getKubernetesManifestObjects(d)
+	existing := make(map[string]bool, len(previous))
+	for _, obj := range previous {
+		existing[obj.key()] = true
+	}
+
+	discovery := newKubernetesDiscovery(client, endpointID)
+	objects := make([]kubernetesManifestObject, 0, len(docs))
+	wanted := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		obj, err := discovery.resolve(doc, d.Get("namespace").(string), time.Minute)
+		if err == nil {
+			err = applyKubernetesManifestObject(client, obj, doc, existing[obj.key()])
+		}
+		if err != nil {
+			// Keep track of the objects created so far and of those not handled yet.
+			for _, prev := range previous {
+				if !wanted[prev.key()] {
+					objects = append(objects, prev)
+				}
+			}
+			setKubernetesManifestObjects(d, objects)
+			return err
+		}
+		wanted[obj.key()] = true
+		objects = append(objects, obj)
+	}
+
+	for i := len(previous) - 1; i >= 0; i-- {
+		if wanted[previous[i].key()] {
+			continue
+		}
+		if err := deleteKubernetesManifestObject(client, previous[i], false); err != nil {
+			for _, prev := range previous[:i+1] {
+				if !wanted[prev.key()] {
+					objects = append(objects, prev)
+				}
+			}
+			setKubernetesManifestObjects(d, objects)
+			return err
+		}
+	}
+
+	setKubernetesManifestObjects(d, objects)
+	return nil
+}
+
+func resourceKubernetesManifestDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+
+	objects := getKubernetesManifestObjects(d)
+	for i := len(objects) - 1; i >= 0; i-- {
+		if err := deleteKubernetesManifestObject(client, objects[i], false); err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// applyKubernetesManifestObject creates obj, or updates it in place when it
+// already exists. Objects whose change touches an immutable field are recreated.
+func applyKubernetesManifestObject(client *APIClient, obj kubernetesManifestObject, doc map[string]interface{}, exists bool) error {
+	if !exists {
+		return createKubernetesManifestObject(client, obj, doc)
+	}
+
+	recreate, err := applyKubernetesObject(client, obj.Path, doc)
+	if err != nil {
+		return fmt.Errorf("failed to update %s %s: %w", obj.Kind, obj.Name, err)
+	}
+	if !recreate {
+		return nil
+	}
+	if err := deleteKubernetesManifestObject(client, obj, true); err != nil {
+		return err
+	}
+	return createKubernetesManifestObject(client, obj, doc)
+}
+
+// createKubernetesManifestObject creates obj with the server-side apply used for
+// updates, so objects of the manifest have a single field manager.
+func createKubernetesManifestObject(client *APIClient, obj kubernetesManifestObject, doc map[string]interface{}) error {
+	if err := createKubernetesObject(client, obj.Path, doc); err != nil {
+		return fmt.Errorf("failed to create %s %s: %w", obj.Kind, obj.Name, err)
+	}
+	return nil
+}
+
+// deleteKubernetesManifestObject deletes obj, ignoring objects that are already
+// gone. With wait set it returns only once the object no longer exists, so that
+// it can be created again.
+func deleteKubernetesManifestObject(client *APIClient, obj kubernetesManifestObject, wait bool) error {
+	resp, err := client.DoRequest(http.MethodDelete, obj.Path+"?propagationPolicy=Background", nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s %s: %w", obj.Kind, obj.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete %s %s (%d): %s", obj.Kind, obj.Name, resp.StatusCode, string(body))
+	}
+
+	deadline := time.Now().Add(2 * time.Minute)
+	for wait {
+		live, err := getKubernetesObject(client, obj.Path)
+		if err != nil {
+			return err
+		}
+		if live == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for %s %s to be deleted", obj.Kind, obj.Name)
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return nil
+}
+
+// kubernetesManifestDocKeys returns the keys the object described by doc may
+// have in state. Without metadata.namespace the object is either namespaced and
+// placed in defaultNamespace, or cluster-scoped with no namespace at all.
+func kubernetesManifestDocKeys(doc map[string]interface{}, defaultNamespace string) []string {
+	apiVersion, _ := doc["apiVersion"].(string)
+	kind, _ := doc["kind"].(string)
+	metadata, _ := doc["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	obj := kubernetesManifestObject{APIVersion: apiVersion, Kind: kind, Name: name}
+
+	if namespace, _ := metadata["namespace"].(string); namespace != "" {
+		obj.Namespace = namespace
+		return []string{obj.key()}
+	}
+	keys := []string{obj.key()}
+	obj.Namespace = defaultNamespace
+	return append(keys, obj.key())
+}
+
+func getKubernetesManifestObjects(d *schema.ResourceData) []kubernetesManifestObject {
+	var objects []kubernetesManifestObject
+	for _, raw := range d.Get("objects").([]interface{}) {
+		m := raw.(map[string]interface{})
+		objects = append(objects, kubernetesManifestObject{
+			APIVersion: m["api_version"].(string),
+			Kind:       m["kind"].(string),
+			Namespace:  m["namespace"].(string),
+			Name:       m["name"].(string),
+			Path:       m["path"].(string),
+		})
+	}
+	return objects
+}
+
+func setKubernetesManifestObjects(d *schema.ResourceData, objects []kubernetesManifestObject) {
+	list := make([]map[string]interface{}, 0, len(objects))
+	for _, obj := range objects {
+		list = append(list, obj.toMap())
+	}
+	d.Set("objects", list)
+}
+
+// suppressEquivalentManifestDocuments ignores formatting-only differences
+// between multi-document manifests.
+func suppressEquivalentManifestDocuments(k, old, new string, d *schema.ResourceData) bool {
+	return manifestDocumentsEquivalent(old, new)
+}
+
+// manifestDocumentsEquivalent reports whether two multi-document manifests hold the same objects.
+func manifestDocumentsEquivalent(a, b string) bool {
+	if strings.TrimSpace(a) == strings.TrimSpace(b) {
+		return true
+	}
+	aDocs, _, err := parseManifestDocuments(a)
+	if err != nil {
+		return false
+	}
+	bDocs, _, err := parseManifestDocuments(b)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(aDocs, bDocs)
+}
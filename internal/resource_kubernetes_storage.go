@@ -1,8 +1,6 @@
 package internal
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,6 +15,9 @@ func resourceKubernetesStorage() *schema.Resource {
 		Read:   resourceKubernetesStorageRead,
 		Update: resourceKubernetesStorageUpdate,
 		Delete: resourceKubernetesStorageDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceKubernetesStorageImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"endpoint_id": {
@@ -25,8 +26,9 @@ func resourceKubernetesStorage() *schema.Resource {
 				ForceNew: true,
 			},
 			"manifest": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentYAML,
 			},
 		},
 	}
@@ -52,32 +54,11 @@ func resourceKubernetesStorageCreate(d *schema.ResourceData, meta interface{}) e
 		return fmt.Errorf("missing metadata.name in manifest")
 	}
 
-	jsonBody, err := json.Marshal(parsed)
-	if err != nil {
-		return fmt.Errorf("failed to encode manifest body: %w", err)
-	}
-
-	url := fmt.Sprintf("%s/endpoints/%d/kubernetes/apis/storage.k8s.io/v1/storageclasses", client.Endpoint, endpointID)
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
+	if err := createKubernetesObject(client, kubernetesStoragePath(endpointID, name), parsed); err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", client.APIKey)
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to create Kubernetes Job: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to create Job (%d): %s", resp.StatusCode, string(body))
-	}
-
-	d.SetId(fmt.Sprintf("%d:%s:%s", endpointID, name))
+	d.SetId(fmt.Sprintf("%d:%s", endpointID, name))
 	return nil
 }
 
@@ -110,6 +91,14 @@ func resourceKubernetesStorageDelete(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceKubernetesStorageUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+
+	endpointID, name := parseStorageID(d.Id())
+	recreate, err := updateKubernetesManifest(d, client, kubernetesStoragePath(endpointID, name), name)
+	if err != nil || !recreate {
+		return err
+	}
+
 	if err := resourceKubernetesStorageDelete(d, meta); err != nil {
 		return fmt.Errorf("delete during update failed: %w", err)
 	}
@@ -117,15 +106,39 @@ func resourceKubernetesStorageUpdate(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceKubernetesStorageRead(d *schema.ResourceData, meta interface{}) error {
-	return nil
+	client := meta.(*APIClient)
+
+	endpointID, name := parseStorageID(d.Id())
+	return readKubernetesManifest(d, client, kubernetesStoragePath(endpointID, name))
+}
+
+func resourceKubernetesStorageImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*APIClient)
+
+	endpointID, name := parseStorageID(d.Id())
+	if name == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected <endpointId>:<name>", d.Id())
+	}
+
+	path := kubernetesStoragePath(endpointID, name)
+	if err := importKubernetesManifest(d, client, path); err != nil {
+		return nil, err
+	}
+	d.Set("endpoint_id", endpointID)
+
+	return []*schema.ResourceData{d}, nil
 }
 
 func parseStorageID(id string) (endpointID int, name string) {
 	parts := strings.SplitN(id, ":", 3)
-	if len(parts) != 3 {
+	if len(parts) < 2 {
 		return 0, ""
 	}
 	fmt.Sscanf(parts[0], "%d", &endpointID)
 	name = parts[1]
 	return
 }
+
+func kubernetesStoragePath(endpointID int, name string) string {
+	return fmt.Sprintf("/endpoints/%d/kubernetes/apis/storage.k8s.io/v1/storageclasses/%s", endpointID, name)
+}
@@ -1,8 +1,6 @@
 package internal
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,6 +15,9 @@ func resourceKubernetesVolumes() *schema.Resource {
 		Read:   resourceKubernetesVolumesRead,
 		Update: resourceKubernetesVolumesUpdate,
 		Delete: resourceKubernetesVolumesDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceKubernetesVolumesImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"endpoint_id": {
@@ -44,8 +45,9 @@ func resourceKubernetesVolumes() *schema.Resource {
 				},
 			},
 			"manifest": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentYAML,
 			},
 		},
 	}
@@ -73,33 +75,13 @@ func resourceKubernetesVolumesCreate(d *schema.ResourceData, meta interface{}) e
 		return fmt.Errorf("missing metadata.name in manifest")
 	}
 
-	jsonBody, err := json.Marshal(parsed)
-	if err != nil {
-		return fmt.Errorf("failed to encode manifest body: %w", err)
-	}
-
-	url, err := volumeAPIURL(client.Endpoint, endpointID, namespace, volType, false)
+	path, err := volumeAPIURL("", endpointID, namespace, volType, true, name)
 	if err != nil {
 		return err
 	}
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
-	if err != nil {
+	if err := createKubernetesObject(client, path, parsed); err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", client.APIKey)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to create Kubernetes volume: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to create volume (%d): %s", resp.StatusCode, string(body))
-	}
 
 	d.SetId(fmt.Sprintf("%d:%s:%s:%s", endpointID, namespace, volType, name))
 	return nil
@@ -137,6 +119,22 @@ func resourceKubernetesVolumesDelete(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceKubernetesVolumesUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+
+	endpointID, namespace, volType, name := parseVolumesID(d.Id())
+	recreate := d.HasChange("namespace") || d.HasChange("type")
+	var err error
+	if !recreate {
+		path, pathErr := volumeAPIURL("", endpointID, namespace, volType, true, name)
+		if pathErr != nil {
+			return pathErr
+		}
+		recreate, err = updateKubernetesManifest(d, client, path, name)
+	}
+	if err != nil || !recreate {
+		return err
+	}
+
 	if err := resourceKubernetesVolumesDelete(d, meta); err != nil {
 		return fmt.Errorf("delete during update failed: %w", err)
 	}
@@ -144,8 +142,36 @@ func resourceKubernetesVolumesUpdate(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceKubernetesVolumesRead(d *schema.ResourceData, meta interface{}) error {
-	// Optional: implement if needed
-	return nil
+	client := meta.(*APIClient)
+
+	endpointID, namespace, volType, name := parseVolumesID(d.Id())
+	path, err := volumeAPIURL("", endpointID, namespace, volType, true, name)
+	if err != nil {
+		return err
+	}
+	return readKubernetesManifest(d, client, path)
+}
+
+func resourceKubernetesVolumesImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*APIClient)
+
+	endpointID, namespace, volType, name := parseVolumesID(d.Id())
+	if name == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected <endpointId>:<namespace>:<type>:<name>", d.Id())
+	}
+
+	path, err := volumeAPIURL("", endpointID, namespace, volType, true, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := importKubernetesManifest(d, client, path); err != nil {
+		return nil, err
+	}
+	d.Set("endpoint_id", endpointID)
+	d.Set("namespace", namespace)
+	d.Set("type", volType)
+
+	return []*schema.ResourceData{d}, nil
 }
 
 func parseVolumesID(id string) (endpointID int, namespace, volType, name string) {
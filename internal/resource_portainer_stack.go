@@ -2,6 +2,7 @@ package internal
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,7 +11,10 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -20,6 +24,13 @@ func resourcePortainerStack() *schema.Resource {
 		Read:   resourcePortainerStackRead,
 		Delete: resourcePortainerStackDelete,
 		Update: resourcePortainerStackUpdate,
+		Importer: &schema.ResourceImporter{
+			State: resourcePortainerStackImport,
+		},
+		CustomizeDiff: resourcePortainerStackCustomizeDiff,
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
+			validateStackComposeFile,
+		},
 		Schema: map[string]*schema.Schema{
 			"deployment_type": {
 				Type:        schema.TypeString,
@@ -32,13 +43,32 @@ func resourcePortainerStack() *schema.Resource {
 				Required:    true,
 				Description: "Creation method: 'string', 'file', 'repository', or 'url'",
 				ForceNew:    true,
+				// Portainer does not record how the file of a stack was provided, so
+				// it is unknown after import until the next apply stores the configured
+				// method. The content is compared through stack_file_content instead.
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return old == "" && d.Id() != "" && stackFileMethods[new]
+				},
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the stack; it can only change in place together with a migration",
+			},
+			"endpoint_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Environment of the stack; changing it migrates standalone and swarm stacks",
+			},
+			"swarm_id":  {Type: schema.TypeString, Optional: true, Computed: true},
+			"namespace": {Type: schema.TypeString, Optional: true, ForceNew: true},
+			"stack_file_content": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressEquivalentYAML,
 			},
-			"name":                {Type: schema.TypeString, Required: true, ForceNew: true},
-			"endpoint_id":         {Type: schema.TypeInt, Required: true, ForceNew: true},
-			"swarm_id":            {Type: schema.TypeString, Optional: true, ForceNew: true, Computed: true},
-			"namespace":           {Type: schema.TypeString, Optional: true, ForceNew: true},
-			"stack_file_content":  {Type: schema.TypeString, Optional: true},
-			"stack_file_path":     {Type: schema.TypeString, Optional: true, ForceNew: true},
+			"stack_file_path":     {Type: schema.TypeString, Optional: true},
 			"repository_url":      {Type: schema.TypeString, Optional: true, ForceNew: true},
 			"repository_username": {Type: schema.TypeString, Optional: true},
 			"repository_password": {Type: schema.TypeString, Optional: true, Sensitive: true},
@@ -52,7 +82,47 @@ func resourcePortainerStack() *schema.Resource {
 				Optional: true,
 				Default:  "docker-compose.yml",
 			},
-			"manifest_url":   {Type: schema.TypeString, Optional: true, ForceNew: true},
+			"custom_template_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"stack_file_content", "stack_file_path"},
+				Description:   "ID of a custom template to deploy with the string method; its rendered file becomes stack_file_content",
+			},
+			"template_variables": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				RequiredWith: []string{"custom_template_id"},
+				Description:  "Values of the custom template variables; variables not set here use their default",
+			},
+			"additional_files": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Further compose files in the repository, applied in order on top of file_path_in_repository",
+			},
+			"support_relative_path": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Resolve relative paths of bind mounts against the repository checkout (standalone and swarm)",
+			},
+			"filesystem_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Host path where the repository is checked out when support_relative_path is set",
+			},
+			"manifest_url": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				// Portainer does not keep the URL of a stack, so it is unknown after
+				// import, like the method.
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					method, _ := d.GetChange("method")
+					return old == "" && d.Id() != "" && method.(string) == ""
+				},
+			},
 			"compose_format": {Type: schema.TypeBool, Optional: true, Default: false, ForceNew: true},
 			"env": {
 				Type:     schema.TypeList,
@@ -64,7 +134,84 @@ func resourcePortainerStack() *schema.Resource {
 					},
 				},
 			},
-			"tlsskip_verify": {Type: schema.TypeBool, Optional: true, Default: false, ForceNew: true},
+			"env_file_content":        envFileContentSchema(),
+			"sensitive_env":           sensitiveEnvSchema(),
+			"sensitive_env_revisions": sensitiveEnvRevisionsSchema(),
+			"tlsskip_verify":          {Type: schema.TypeBool, Optional: true, Default: false, ForceNew: true},
+			"prune": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Remove services that are no longer part of the stack file when the stack is updated",
+			},
+			"pull_image": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Pull the latest version of the images when the stack is updated",
+			},
+			"auto_update": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "GitOps updates of a repository stack, by polling and/or through a webhook",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"interval": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Polling interval such as '5m'; empty disables polling",
+						},
+						"webhook": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Enable a webhook that triggers an update",
+						},
+						"force_update": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Redeploy even when the repository has not changed",
+						},
+						"force_pull_image": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Pull the images on every update",
+						},
+					},
+				},
+			},
+			"webhook_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "URL of the auto-update webhook, when enabled",
+			},
+			"active": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the stack is running; false stops it (standalone and swarm stacks only)",
+			},
+			"wait_for_healthy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Wait after create and update until all containers or swarm tasks of the stack are running and healthy",
+			},
+			"health_timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     300,
+				Description: "Seconds to wait for the stack to become healthy",
+			},
+			"access_control": accessControlSchema(),
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Stack status reported by Portainer: 'active' or 'inactive'",
+			},
 		},
 	}
 }
@@ -82,39 +229,468 @@ func resourcePortainerStackCreate(d *schema.ResourceData, meta interface{}) erro
 		d.Set("swarm_id", swarmID)
 	}
 
+	var create func(*schema.ResourceData, *APIClient) error
 	switch deployment {
 	case "standalone":
 		switch method {
 		case "string":
-			return createStackStandaloneString(d, client)
+			create = createStackStandaloneString
 		case "file":
-			return createStackStandaloneFile(d, client)
+			create = createStackStandaloneFile
 		case "repository":
-			return createStackStandaloneRepo(d, client)
+			create = createStackStandaloneRepo
 		}
 	case "swarm":
 		switch method {
 		case "string":
-			return createStackSwarmString(d, client)
+			create = createStackSwarmString
 		case "file":
-			return createStackSwarmFile(d, client)
+			create = createStackSwarmFile
 		case "repository":
-			return createStackSwarmRepo(d, client)
+			create = createStackSwarmRepo
 		}
 	case "kubernetes":
 		switch method {
 		case "string":
-			return createStackK8sString(d, client)
+			create = createStackK8sString
 		case "repository":
-			return createStackK8sRepo(d, client)
+			create = createStackK8sRepo
 		case "url":
-			return createStackK8sURL(d, client)
+			create = createStackK8sURL
 		}
 	}
-	return fmt.Errorf("invalid combination of deployment_type and method")
+	if create == nil {
+		return fmt.Errorf("invalid combination of deployment_type and method")
+	}
+
+	if err := create(d, client); err != nil {
+		return err
+	}
+	if !d.Get("active").(bool) {
+		if err := setStackActive(d, client, false); err != nil {
+			return err
+		}
+	}
+	if err := updateStackAccessControl(d, client); err != nil {
+		return err
+	}
+	if err := waitForStackHealthyIfRequested(d, client); err != nil {
+		return err
+	}
+	return resourcePortainerStackRead(d, meta)
 }
 
 func resourcePortainerStackRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+
+	stack, err := fetchStack(client, d.Id())
+	if err != nil {
+		return err
+	}
+	if stack == nil {
+		d.SetId("")
+		return nil
+	}
+
+	return setStackFields(d, client, stack)
+}
+
+// resourcePortainerStackImport accepts "<endpointId>:<stackId>", "<endpointId>:<name>" or a bare "<stackId>".
+// stackFileMethods are the methods that deploy a stack file Portainer keeps,
+// as opposed to a Git repository.
+var stackFileMethods = map[string]bool{"string": true, "file": true, "url": true}
+
+// validateStackComposeFile warns about the compose file given inline or from
+// disk: service keys swarm ignores and variables no env source sets. Warnings
+// returned here are shown to the user, unlike those of a CustomizeDiff.
+func validateStackComposeFile(ctx context.Context, req schema.ValidateResourceConfigFuncRequest, resp *schema.ValidateResourceConfigFuncResponse) {
+	config := req.RawConfig
+	if config.IsNull() || !config.IsKnown() {
+		return
+	}
+	deployment := rawConfigString(config.GetAttr("deployment_type"))
+	if deployment != "standalone" && deployment != "swarm" {
+		return
+	}
+
+	attr, content := "stack_file_content", rawConfigString(config.GetAttr("stack_file_content"))
+	if content == "" && rawConfigString(config.GetAttr("method")) == "file" {
+		path := rawConfigString(config.GetAttr("stack_file_path"))
+		if path == "" {
+			return
+		}
+		// An unreadable file is reported by resourcePortainerStackCustomizeDiff.
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return
+		}
+		attr, content = "stack_file_path", string(data)
+	}
+	if content == "" {
+		return
+	}
+
+	resp.Diagnostics = append(resp.Diagnostics, composeWarningDiagnostics(attr, content, rawConfigEnv(config), deployment == "swarm")...)
+}
+
+// configuredStackMethod returns the method of the configuration, which can
+// differ from the method in the state of an imported stack.
+func configuredStackMethod(d *schema.ResourceDiff) string {
+	if config := d.GetRawConfig(); !config.IsNull() {
+		if method := rawConfigString(config.GetAttr("method")); method != "" {
+			return method
+		}
+	}
+	return d.Get("method").(string)
+}
+
+func resourcePortainerStackImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*APIClient)
+	id := d.Id()
+
+	stackID := id
+	endpointID := 0
+	if parts := strings.SplitN(id, ":", 2); len(parts) == 2 {
+		var err error
+		endpointID, err = strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid import ID %q, expected <endpointId>:<stackId> or <endpointId>:<name>", id)
+		}
+		if _, err := strconv.Atoi(parts[1]); err == nil {
+			stackID = parts[1]
+		} else {
+			found, err := findStackIDByName(client, endpointID, parts[1])
+			if err != nil {
+				return nil, err
+			}
+			stackID = strconv.Itoa(found)
+		}
+	}
+
+	stack, err := fetchStack(client, stackID)
+	if err != nil {
+		return nil, err
+	}
+	if stack == nil {
+		return nil, fmt.Errorf("stack %s not found", stackID)
+	}
+	if endpointID != 0 && stack.EndpointID != endpointID {
+		return nil, fmt.Errorf("stack %s belongs to environment %d, not %d", stackID, stack.EndpointID, endpointID)
+	}
+
+	d.SetId(stackID)
+	// Settings Portainer does not report start at their defaults, so a
+	// configuration that leaves them unset plans no change.
+	for key, s := range resourcePortainerStack().Schema {
+		if s.Default != nil {
+			d.Set(key, s.Default)
+		}
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourcePortainerStackCustomizeDiff plans what Terraform cannot see from
+// the configuration alone: revisions of changed sensitive_env values, migration
+// to another environment or swarm (or replacement where Portainer cannot
+// migrate), a redeploy when the file behind stack_file_path or the rendered
+// custom template changes, and validation of the compose file.
+func resourcePortainerStackCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := sensitiveEnvRevisionsCustomizeDiff(ctx, d, meta); err != nil {
+		return err
+	}
+	if d.Get("deployment_type").(string) == "kubernetes" && !d.Get("active").(bool) {
+		return fmt.Errorf("active = false is only supported for standalone and swarm stacks")
+	}
+	if d.Get("deployment_type").(string) == "kubernetes" && d.Get("wait_for_healthy").(bool) {
+		return fmt.Errorf("wait_for_healthy is only supported for standalone and swarm stacks")
+	}
+
+	// Portainer migrates Docker stacks to another environment, optionally
+	// renaming them. Kubernetes stacks, and renames alone, need a new stack.
+	if d.Id() != "" {
+		migrate := d.HasChanges("endpoint_id", "swarm_id")
+		if d.Get("deployment_type").(string) == "kubernetes" && migrate {
+			for _, key := range []string{"endpoint_id", "swarm_id"} {
+				if d.HasChange(key) {
+					if err := d.ForceNew(key); err != nil {
+						return err
+					}
+				}
+			}
+		} else if d.HasChange("name") && !migrate {
+			if err := d.ForceNew("name"); err != nil {
+				return err
+			}
+		}
+
+		// A swarm stack moved to another environment joins that environment's
+		// swarm unless swarm_id is set explicitly.
+		if d.Get("deployment_type").(string) == "swarm" && d.HasChange("endpoint_id") && d.GetRawConfig().GetAttr("swarm_id").IsNull() {
+			if err := d.SetNewComputed("swarm_id"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if configuredStackMethod(d) == "file" && d.NewValueKnown("stack_file_path") {
+		content, err := os.ReadFile(d.Get("stack_file_path").(string))
+		if err != nil {
+			return fmt.Errorf("failed to read stack_file_path: %w", err)
+		}
+		if !yamlEquivalent(d.Get("stack_file_content").(string), string(content)) {
+			if err := d.SetNew("stack_file_content", string(content)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// A stack from a custom template deploys the rendered template, so changes
+	// to the template or to its variables redeploy the stack.
+	if d.Get("custom_template_id").(int) != 0 || !d.NewValueKnown("custom_template_id") {
+		if configuredStackMethod(d) != "string" {
+			return fmt.Errorf("custom_template_id requires method = \"string\"")
+		}
+		if !d.NewValueKnown("custom_template_id") || !d.NewValueKnown("template_variables") {
+			if err := d.SetNewComputed("stack_file_content"); err != nil {
+				return err
+			}
+		} else {
+			content, defaults, err := fetchCustomTemplateFile(meta.(*APIClient), d.Get("custom_template_id").(int))
+			if err != nil {
+				return err
+			}
+			values := map[string]string{}
+			for k, v := range d.Get("template_variables").(map[string]interface{}) {
+				values[k] = v.(string)
+			}
+			rendered, err := renderCustomTemplate(content, values, defaults)
+			if err != nil {
+				return fmt.Errorf("failed to render custom template %d: %w", d.Get("custom_template_id").(int), err)
+			}
+			if !yamlEquivalent(d.Get("stack_file_content").(string), rendered) {
+				if err := d.SetNew("stack_file_content", rendered); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Validate compose files given inline or from disk; repository files are
+	// only known to Portainer.
+	method := configuredStackMethod(d)
+	deployment := d.Get("deployment_type").(string)
+	if (method == "string" || method == "file") && deployment != "kubernetes" && d.NewValueKnown("stack_file_content") {
+		content := d.Get("stack_file_content").(string)
+		if content == "" {
+			return nil
+		}
+
+		var env map[string]string
+		if d.NewValueKnown("env") && d.NewValueKnown("env_file_content") && d.NewValueKnown("sensitive_env") {
+			env = map[string]string{}
+			for _, e := range mergeEnvSources(d.Get("env_file_content").(string), d.Get("env").([]interface{}), d.Get("sensitive_env").(map[string]interface{})) {
+				env[e["name"]] = e["value"]
+			}
+		}
+
+		// Warnings are reported by validateStackComposeFile.
+		if _, err := validateComposeFile(content, env, deployment == "swarm"); err != nil {
+			return fmt.Errorf("stack_file_content is not a valid compose file: %w", err)
+		}
+	}
+	return nil
+}
+
+// portainerStack is the subset of the Portainer stack object managed by this provider.
+type portainerStack struct {
+	ID                  int      `json:"Id"`
+	Name                string   `json:"Name"`
+	Type                int      `json:"Type"`
+	EndpointID          int      `json:"EndpointId"`
+	SwarmID             string   `json:"SwarmId"`
+	EntryPoint          string   `json:"EntryPoint"`
+	AdditionalFiles     []string `json:"AdditionalFiles"`
+	SupportRelativePath bool     `json:"SupportRelativePath"`
+	FilesystemPath      string   `json:"FilesystemPath"`
+	Namespace           string   `json:"Namespace"`
+	IsComposeFormat     bool     `json:"IsComposeFormat"`
+	Status              int      `json:"Status"`
+	Env                 []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"Env"`
+	ResourceControl *portainerResourceControl `json:"ResourceControl"`
+	GitConfig       *struct {
+		URL            string `json:"URL"`
+		ReferenceName  string `json:"ReferenceName"`
+		ConfigFilePath string `json:"ConfigFilePath"`
+		TLSSkipVerify  bool   `json:"TLSSkipVerify"`
+		Authentication *struct {
+			Username string `json:"Username"`
+		} `json:"Authentication"`
+	} `json:"GitConfig"`
+	AutoUpdate *struct {
+		Interval       string `json:"Interval"`
+		Webhook        string `json:"Webhook"`
+		ForceUpdate    bool   `json:"ForceUpdate"`
+		ForcePullImage bool   `json:"ForcePullImage"`
+	} `json:"AutoUpdate"`
+}
+
+// fetchStack returns the stack with the given ID, or nil if it does not exist.
+func fetchStack(client *APIClient, stackID string) (*portainerStack, error) {
+	resp, err := client.DoRequest("GET", fmt.Sprintf("/stacks/%s", stackID), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, nil
+	} else if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to read stack: %s", string(data))
+	}
+
+	var stack portainerStack
+	if err := json.NewDecoder(resp.Body).Decode(&stack); err != nil {
+		return nil, fmt.Errorf("failed to decode stack: %w", err)
+	}
+	return &stack, nil
+}
+
+func fetchStackFileContent(client *APIClient, stackID string) (string, error) {
+	resp, err := client.DoRequest("GET", fmt.Sprintf("/stacks/%s/file", stackID), nil, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to read stack file: %s", string(data))
+	}
+
+	var file struct {
+		StackFileContent string `json:"StackFileContent"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&file); err != nil {
+		return "", fmt.Errorf("failed to decode stack file: %w", err)
+	}
+	return file.StackFileContent, nil
+}
+
+func findStackIDByName(client *APIClient, endpointID int, name string) (int, error) {
+	resp, err := client.DoRequest("GET", "/stacks", nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("failed to list stacks: %s", string(data))
+	}
+
+	var stacks []portainerStack
+	if err := json.NewDecoder(resp.Body).Decode(&stacks); err != nil {
+		return 0, fmt.Errorf("failed to decode stacks: %w", err)
+	}
+
+	for _, s := range stacks {
+		if s.EndpointID == endpointID && s.Name == name {
+			return s.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("stack %q not found on endpoint %d", name, endpointID)
+}
+
+// setStackFields copies the stack returned by the API into the resource state.
+func setStackFields(d *schema.ResourceData, client *APIClient, stack *portainerStack) error {
+	switch stack.Type {
+	case 1:
+		d.Set("deployment_type", "swarm")
+	case 2:
+		d.Set("deployment_type", "standalone")
+	case 3:
+		d.Set("deployment_type", "kubernetes")
+	}
+
+	d.Set("name", stack.Name)
+	d.Set("endpoint_id", stack.EndpointID)
+	d.Set("swarm_id", stack.SwarmID)
+	d.Set("namespace", stack.Namespace)
+	d.Set("compose_format", stack.IsComposeFormat)
+
+	switch stack.Status {
+	case 1:
+		d.Set("status", "active")
+		d.Set("active", true)
+	case 2:
+		d.Set("status", "inactive")
+		d.Set("active", false)
+	default:
+		d.Set("status", "")
+	}
+
+	if err := setAccessControl(d, stack.ResourceControl); err != nil {
+		return err
+	}
+
+	actual := make([]map[string]string, 0, len(stack.Env))
+	for _, e := range stack.Env {
+		actual = append(actual, map[string]string{"name": e.Name, "value": e.Value})
+	}
+	envFile, env, sensitive := splitEnvSources(actual, d.Get("env_file_content").(string),
+		d.Get("env").([]interface{}), d.Get("sensitive_env").(map[string]interface{}))
+	d.Set("env_file_content", envFile)
+	if err := d.Set("env", env); err != nil {
+		return fmt.Errorf("failed to set env: %w", err)
+	}
+	if err := d.Set("sensitive_env", sensitive); err != nil {
+		return fmt.Errorf("failed to set sensitive_env: %w", err)
+	}
+
+	if stack.GitConfig != nil {
+		if _, ok := d.GetOk("method"); !ok {
+			d.Set("method", "repository")
+		}
+		d.Set("repository_url", stack.GitConfig.URL)
+		d.Set("repository_reference_name", stack.GitConfig.ReferenceName)
+		d.Set("file_path_in_repository", stack.GitConfig.ConfigFilePath)
+		d.Set("tlsskip_verify", stack.GitConfig.TLSSkipVerify)
+		d.Set("additional_files", stack.AdditionalFiles)
+		d.Set("support_relative_path", stack.SupportRelativePath)
+		d.Set("filesystem_path", stack.FilesystemPath)
+		if stack.GitConfig.Authentication != nil {
+			d.Set("repository_username", stack.GitConfig.Authentication.Username)
+		}
+
+		autoUpdate := []map[string]interface{}{}
+		webhookURL := ""
+		if au := stack.AutoUpdate; au != nil && (au.Interval != "" || au.Webhook != "") {
+			autoUpdate = append(autoUpdate, map[string]interface{}{
+				"interval":         au.Interval,
+				"webhook":          au.Webhook != "",
+				"force_update":     au.ForceUpdate,
+				"force_pull_image": au.ForcePullImage,
+			})
+			if au.Webhook != "" {
+				webhookURL = stackWebhookURL(client, au.Webhook)
+			}
+		}
+		if err := d.Set("auto_update", autoUpdate); err != nil {
+			return fmt.Errorf("failed to set auto_update: %w", err)
+		}
+		d.Set("webhook_url", webhookURL)
+		return nil
+	}
+
+	content, err := fetchStackFileContent(client, strconv.Itoa(stack.ID))
+	if err != nil {
+		return err
+	}
+	d.Set("stack_file_content", content)
 	return nil
 }
 
@@ -171,15 +747,159 @@ func resourcePortainerStackDelete(d *schema.ResourceData, meta interface{}) erro
 
 func resourcePortainerStackUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*APIClient)
+	active := d.Get("active").(bool)
+
+	// Store the method and URL of an imported stack, which its plan ignored.
+	if config := d.GetRawConfig(); d.Get("method").(string) == "" && !config.IsNull() {
+		d.Set("method", rawConfigString(config.GetAttr("method")))
+		d.Set("manifest_url", rawConfigString(config.GetAttr("manifest_url")))
+	}
+
+	if d.HasChanges("endpoint_id", "swarm_id") {
+		if err := migrateStack(d, client); err != nil {
+			return err
+		}
+	}
+
+	// Start a stack before redeploying it, and stop it only afterwards, since
+	// Portainer does not redeploy inactive stacks.
+	if d.HasChange("active") && active {
+		if err := setStackActive(d, client, true); err != nil {
+			return err
+		}
+	}
+	if d.HasChange("access_control") {
+		if err := updateStackAccessControl(d, client); err != nil {
+			return err
+		}
+	}
+	if d.HasChangesExcept("active", "endpoint_id", "swarm_id", "name", "wait_for_healthy", "health_timeout", "access_control", "prune", "pull_image") {
+		if err := updateStackDeployment(d, client); err != nil {
+			return err
+		}
+	}
+	if d.HasChange("active") && !active {
+		if err := setStackActive(d, client, false); err != nil {
+			return err
+		}
+	}
+	if d.HasChangesExcept("active", "wait_for_healthy", "health_timeout", "access_control", "prune", "pull_image") || d.HasChange("active") && active {
+		if err := waitForStackHealthyIfRequested(d, client); err != nil {
+			return err
+		}
+	}
+	return resourcePortainerStackRead(d, meta)
+}
+
+// waitForStackHealthyIfRequested waits for a running stack to become healthy
+// when wait_for_healthy is set.
+func waitForStackHealthyIfRequested(d *schema.ResourceData, client *APIClient) error {
+	if !d.Get("wait_for_healthy").(bool) || !d.Get("active").(bool) {
+		return nil
+	}
+	timeout := time.Duration(d.Get("health_timeout").(int)) * time.Second
+	return waitForStackHealthy(client, d.Get("endpoint_id").(int), d.Get("deployment_type").(string), d.Get("name").(string), timeout)
+}
+
+// migrateStack moves the stack to the planned environment and swarm, renaming
+// it if name changed too. The stack keeps its ID, webhook and resource control.
+func migrateStack(d *schema.ResourceData, client *APIClient) error {
+	oldEndpointID, newEndpointID := d.GetChange("endpoint_id")
+
+	swarmID := d.Get("swarm_id").(string)
+	if d.Get("deployment_type").(string) == "swarm" && swarmID == "" {
+		var err error
+		if swarmID, err = fetchSwarmID(client, newEndpointID.(int)); err != nil {
+			return fmt.Errorf("failed to fetch swarm_id: %w", err)
+		}
+		d.Set("swarm_id", swarmID)
+	}
+
+	payload := map[string]interface{}{
+		"endpointID": newEndpointID.(int),
+		"swarmID":    swarmID,
+	}
+	if d.HasChange("name") {
+		payload["name"] = d.Get("name").(string)
+	}
+
+	path := fmt.Sprintf("/stacks/%s/migrate?endpointId=%d", d.Id(), oldEndpointID.(int))
+	resp, err := client.DoRequest("POST", path, nil, payload)
+	if err != nil {
+		return fmt.Errorf("failed to migrate stack: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to migrate stack to environment %d: %s", newEndpointID.(int), string(data))
+	}
+	return nil
+}
+
+// updateStackAccessControl applies access_control to the resource control of the stack.
+func updateStackAccessControl(d *schema.ResourceData, client *APIClient) error {
+	if len(d.Get("access_control").([]interface{})) == 0 {
+		return nil
+	}
+	stack, err := fetchStack(client, d.Id())
+	if err != nil {
+		return err
+	}
+	if stack == nil {
+		return fmt.Errorf("stack %s not found", d.Id())
+	}
+	// Portainer identifies the resource control of a stack by "<endpointId>_<name>".
+	resourceID := fmt.Sprintf("%d_%s", stack.EndpointID, stack.Name)
+	return applyAccessControl(d, client, stack.ResourceControl, resourceID, resourceControlTypeStack)
+}
+
+// setStackActive starts or stops the stack.
+func setStackActive(d *schema.ResourceData, client *APIClient, active bool) error {
+	action := "stop"
+	if active {
+		action = "start"
+	}
+
+	path := fmt.Sprintf("/stacks/%s/%s?endpointId=%d", d.Id(), action, d.Get("endpoint_id").(int))
+	resp, err := client.DoRequest("POST", path, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to %s stack: %w", action, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to %s stack: %s", action, string(data))
+	}
+	return nil
+}
+
+// updateStackDeployment applies changes to the stack file, Git settings and
+// environment variables, redeploying the stack.
+func updateStackDeployment(d *schema.ResourceData, client *APIClient) error {
 	stackID := d.Id()
 	endpointID := d.Get("endpoint_id").(int)
 	method := d.Get("method").(string)
 
 	if method == "repository" {
+		if d.HasChanges("auto_update", "additional_files", "support_relative_path", "filesystem_path") {
+			if err := updateStackGitSettings(d, client); err != nil {
+				return err
+			}
+			// Changing only the auto-update settings does not redeploy the stack.
+			if !d.HasChangesExcept("auto_update", "active", "wait_for_healthy", "health_timeout", "access_control", "prune", "pull_image") {
+				return nil
+			}
+		}
+
 		payload := map[string]interface{}{
-			"env":                      flattenEnvList(d.Get("env").([]interface{})),
-			"prune":                    true,
-			"pullImage":                false,
+			"env":                      stackEnv(d),
+			"prune":                    d.Get("prune").(bool),
+			"pullImage":                d.Get("pull_image").(bool),
+			"additionalFiles":          d.Get("additional_files").([]interface{}),
+			"supportRelativePath":      d.Get("support_relative_path").(bool),
+			"filesystemPath":           d.Get("filesystem_path").(string),
 			"repositoryAuthentication": true,
 			"repositoryUsername":       d.Get("repository_username").(string),
 			"repositoryPassword":       d.Get("repository_password").(string),
@@ -213,12 +933,13 @@ func resourcePortainerStackUpdate(d *schema.ResourceData, meta interface{}) erro
 		return nil
 	}
 
-	// fallback to default update (string based)
+	// String and file stacks are redeployed with the new content; for the file
+	// method CustomizeDiff has loaded the file into stack_file_content.
 	payload := map[string]interface{}{
-		"env":              flattenEnvList(d.Get("env").([]interface{})),
+		"env":              stackEnv(d),
 		"stackFileContent": d.Get("stack_file_content").(string),
-		"prune":            true,
-		"pullImage":        false,
+		"prune":            d.Get("prune").(bool),
+		"pullImage":        d.Get("pull_image").(bool),
 	}
 
 	jsonBody, err := json.Marshal(payload)
@@ -248,6 +969,76 @@ func resourcePortainerStackUpdate(d *schema.ResourceData, meta interface{}) erro
 	return nil
 }
 
+// updateStackGitSettings stores the Git and auto-update settings of a
+// repository stack without redeploying it.
+func updateStackGitSettings(d *schema.ResourceData, client *APIClient) error {
+	payload := map[string]interface{}{
+		"autoUpdate":               expandStackAutoUpdate(d),
+		"env":                      stackEnv(d),
+		"prune":                    d.Get("prune").(bool),
+		"additionalFiles":          d.Get("additional_files").([]interface{}),
+		"supportRelativePath":      d.Get("support_relative_path").(bool),
+		"filesystemPath":           d.Get("filesystem_path").(string),
+		"repositoryAuthentication": true,
+		"repositoryUsername":       d.Get("repository_username").(string),
+		"repositoryPassword":       d.Get("repository_password").(string),
+		"repositoryReferenceName":  d.Get("repository_reference_name").(string),
+		"tlsskipVerify":            d.Get("tlsskip_verify").(bool),
+	}
+
+	path := fmt.Sprintf("/stacks/%s/git?endpointId=%d", d.Id(), d.Get("endpoint_id").(int))
+	resp, err := client.DoRequest("POST", path, nil, payload)
+	if err != nil {
+		return fmt.Errorf("failed to update stack git settings: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update stack git settings: %s", string(data))
+	}
+	return nil
+}
+
+// expandStackAutoUpdate builds the autoUpdate payload from the auto_update
+// block, or returns nil when auto-update is disabled. Portainer expects the
+// client to choose the webhook ID, so an existing one is kept to keep
+// webhook_url stable.
+func expandStackAutoUpdate(d *schema.ResourceData) map[string]interface{} {
+	blocks := d.Get("auto_update").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return nil
+	}
+	block := blocks[0].(map[string]interface{})
+
+	webhook := ""
+	if block["webhook"].(bool) {
+		if current := d.Get("webhook_url").(string); current != "" {
+			webhook = current[strings.LastIndex(current, "/")+1:]
+		} else {
+			webhook, _ = uuid.GenerateUUID()
+		}
+	}
+
+	return map[string]interface{}{
+		"interval":       block["interval"].(string),
+		"webhook":        webhook,
+		"forceUpdate":    block["force_update"].(bool),
+		"forcePullImage": block["force_pull_image"].(bool),
+	}
+}
+
+// stackWebhookURL returns the URL that triggers the auto-update webhook with the given ID.
+func stackWebhookURL(client *APIClient, webhook string) string {
+	return fmt.Sprintf("%s/stacks/webhooks/%s", client.Endpoint, webhook)
+}
+
+// stackEnv returns the variables of env_file_content, env and sensitive_env for the Env payload.
+func stackEnv(d *schema.ResourceData) []map[string]string {
+	return mergeEnvSources(d.Get("env_file_content").(string), d.Get("env").([]interface{}),
+		d.Get("sensitive_env").(map[string]interface{}))
+}
+
 func flattenEnvList(envList []interface{}) []map[string]string {
 	var out []map[string]string
 	for _, v := range envList {
@@ -271,7 +1062,7 @@ func createStackStandaloneString(d *schema.ResourceData, client *APIClient) erro
 	payload := map[string]interface{}{
 		"name":             d.Get("name").(string),
 		"stackFileContent": d.Get("stack_file_content").(string),
-		"env":              flattenEnvList(d.Get("env").([]interface{})),
+		"env":              stackEnv(d),
 		"fromAppTemplate":  false,
 	}
 	endpointID := d.Get("endpoint_id").(int)
@@ -311,7 +1102,7 @@ func createStackStandaloneFile(d *schema.ResourceData, client *APIClient) error
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 	writer.WriteField("Name", d.Get("name").(string))
-	writer.WriteField("Env", string(mustJSON(flattenEnvList(d.Get("env").([]interface{})))))
+	writer.WriteField("Env", string(mustJSON(stackEnv(d))))
 
 	part, err := writer.CreateFormFile("file", filepath.Base(path))
 	if err != nil {
@@ -348,14 +1139,18 @@ func createStackStandaloneRepo(d *schema.ResourceData, client *APIClient) error
 	payload := map[string]interface{}{
 		"name":                     d.Get("name").(string),
 		"composeFile":              d.Get("file_path_in_repository").(string),
+		"additionalFiles":          d.Get("additional_files").([]interface{}),
+		"supportRelativePath":      d.Get("support_relative_path").(bool),
+		"filesystemPath":           d.Get("filesystem_path").(string),
 		"repositoryURL":            d.Get("repository_url").(string),
 		"repositoryUsername":       d.Get("repository_username").(string),
 		"repositoryPassword":       d.Get("repository_password").(string),
 		"repositoryReferenceName":  d.Get("repository_reference_name").(string),
 		"repositoryAuthentication": true,
-		"env":                      flattenEnvList(d.Get("env").([]interface{})),
+		"env":                      stackEnv(d),
 		"fromAppTemplate":          false,
 		"tlsskipVerify":            d.Get("tlsskip_verify").(bool),
+		"autoUpdate":               expandStackAutoUpdate(d),
 	}
 	endpointID := d.Get("endpoint_id").(int)
 	url := fmt.Sprintf("%s/stacks/create/standalone/repository?endpointId=%d", client.Endpoint, endpointID)
@@ -389,7 +1184,7 @@ func createStackSwarmString(d *schema.ResourceData, client *APIClient) error {
 	payload := map[string]interface{}{
 		"name":             d.Get("name").(string),
 		"stackFileContent": d.Get("stack_file_content").(string),
-		"env":              flattenEnvList(d.Get("env").([]interface{})),
+		"env":              stackEnv(d),
 		"fromAppTemplate":  false,
 		"swarmID":          d.Get("swarm_id").(string),
 	}
@@ -430,7 +1225,7 @@ func createStackSwarmFile(d *schema.ResourceData, client *APIClient) error {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 	writer.WriteField("Name", d.Get("name").(string))
-	writer.WriteField("Env", string(mustJSON(flattenEnvList(d.Get("env").([]interface{})))))
+	writer.WriteField("Env", string(mustJSON(stackEnv(d))))
 	writer.WriteField("SwarmID", d.Get("swarm_id").(string))
 
 	part, err := writer.CreateFormFile("file", filepath.Base(path))
@@ -468,14 +1263,18 @@ func createStackSwarmRepo(d *schema.ResourceData, client *APIClient) error {
 	payload := map[string]interface{}{
 		"name":                     d.Get("name").(string),
 		"composeFile":              d.Get("file_path_in_repository").(string),
+		"additionalFiles":          d.Get("additional_files").([]interface{}),
+		"supportRelativePath":      d.Get("support_relative_path").(bool),
+		"filesystemPath":           d.Get("filesystem_path").(string),
 		"repositoryURL":            d.Get("repository_url").(string),
 		"repositoryUsername":       d.Get("repository_username").(string),
 		"repositoryPassword":       d.Get("repository_password").(string),
 		"repositoryReferenceName":  d.Get("repository_reference_name").(string),
 		"repositoryAuthentication": true,
-		"env":                      flattenEnvList(d.Get("env").([]interface{})),
+		"env":                      stackEnv(d),
 		"fromAppTemplate":          false,
 		"tlsskipVerify":            d.Get("tlsskip_verify").(bool),
+		"autoUpdate":               expandStackAutoUpdate(d),
 		"swarmID":                  d.Get("swarm_id").(string),
 	}
 	endpointID := d.Get("endpoint_id").(int)
@@ -544,6 +1343,7 @@ func createStackK8sRepo(d *schema.ResourceData, client *APIClient) error {
 	payload := map[string]interface{}{
 		"stackName":                d.Get("name").(string),
 		"manifestFile":             d.Get("file_path_in_repository").(string),
+		"additionalFiles":          d.Get("additional_files").([]interface{}),
 		"namespace":                d.Get("namespace").(string),
 		"composeFormat":            d.Get("compose_format").(bool),
 		"repositoryURL":            d.Get("repository_url").(string),
@@ -552,6 +1352,7 @@ func createStackK8sRepo(d *schema.ResourceData, client *APIClient) error {
 		"repositoryReferenceName":  d.Get("repository_reference_name").(string),
 		"repositoryAuthentication": true,
 		"tlsskipVerify":            d.Get("tlsskip_verify").(bool),
+		"autoUpdate":               expandStackAutoUpdate(d),
 		"fromAppTemplate":          false,
 	}
 	endpointID := d.Get("endpoint_id").(int)
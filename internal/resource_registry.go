@@ -15,6 +15,9 @@ func resourceRegistry() *schema.Resource {
 		Read:   resourceRegistryRead,
 		Delete: resourceRegistryDelete,
 		Update: resourceRegistryUpdate,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name":           {Type: schema.TypeString, Required: true},
@@ -121,6 +124,49 @@ func resourceRegistryCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceRegistryRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+
+	resp, err := client.DoRequest("GET", fmt.Sprintf("/registries/%s", d.Id()), nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		d.SetId("")
+		return nil
+	} else if resp.StatusCode != 200 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to read registry: %s", string(data))
+	}
+
+	var registry struct {
+		Name           string `json:"Name"`
+		Type           int    `json:"Type"`
+		URL            string `json:"URL"`
+		BaseURL        string `json:"BaseURL"`
+		Authentication bool   `json:"Authentication"`
+		Username       string `json:"Username"`
+		Gitlab         struct {
+			InstanceURL string `json:"InstanceURL"`
+		} `json:"Gitlab"`
+		Ecr struct {
+			Region string `json:"Region"`
+		} `json:"Ecr"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&registry); err != nil {
+		return err
+	}
+
+	// The password is never returned by the API and is kept from state.
+	d.Set("name", registry.Name)
+	d.Set("type", registry.Type)
+	d.Set("url", registry.URL)
+	d.Set("base_url", registry.BaseURL)
+	d.Set("authentication", registry.Authentication)
+	d.Set("username", registry.Username)
+	d.Set("instance_url", registry.Gitlab.InstanceURL)
+	d.Set("aws_region", registry.Ecr.Region)
 	return nil
 }
 
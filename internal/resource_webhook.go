@@ -31,6 +31,9 @@ func resourceWebhook() *schema.Resource {
 		Read:   resourceWebhookRead,
 		Delete: resourceWebhookDelete,
 		Update: resourceWebhookUpdate,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 		Schema: map[string]*schema.Schema{
 			"endpoint_id": {
 				Type:     schema.TypeInt,
@@ -92,6 +95,38 @@ func resourceWebhookCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceWebhookRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*APIClient)
+
+	// Portainer has no endpoint returning a single webhook, so look it up in the list.
+	resp, err := client.DoRequest("GET", "/webhooks", nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to list webhooks: %s", string(body))
+	}
+
+	var webhooks []WebhookResponse
+	if err := json.NewDecoder(resp.Body).Decode(&webhooks); err != nil {
+		return err
+	}
+
+	for _, w := range webhooks {
+		if strconv.Itoa(w.ID) != d.Id() {
+			continue
+		}
+		d.Set("endpoint_id", w.EndpointID)
+		d.Set("registry_id", w.RegistryID)
+		d.Set("resource_id", w.ResourceID)
+		d.Set("webhook_type", w.Type)
+		d.Set("token", w.Token)
+		return nil
+	}
+
+	d.SetId("")
 	return nil
 }
 